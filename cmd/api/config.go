@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/service"
+)
+
+// config holds the settings of the API server. Each setting can be given as
+// flag or as environment variable, flags take precedence.
+type config struct {
+	dsn             string
+	httpAddr        string
+	grpcAddr        string
+	geoIPDB         string
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
+	importInterval  time.Duration
+	requireAPIKey   bool
+	trustProxy      bool
+	searchRate      float64
+	bulkRate        float64
+	keySearchRate   float64
+	keyBulkRate     float64
+	cors            service.CORSOptions
+}
+
+// loadConfig loads the config from the environment and the given arguments.
+func loadConfig(args []string) (config, error) {
+	var cfg config
+	var err error
+
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	fs.StringVar(&cfg.dsn, "dsn", envString("MDTHK_DSN", ""),
+		"PostgreSQL connection string (MDTHK_DSN)")
+	fs.StringVar(&cfg.httpAddr, "http-addr", envString("MDTHK_HTTP_ADDR", ":8080"),
+		"address of the REST API (MDTHK_HTTP_ADDR)")
+	fs.StringVar(&cfg.grpcAddr, "grpc-addr", envString("MDTHK_GRPC_ADDR", ":8081"),
+		"address of the gRPC API, empty to disable (MDTHK_GRPC_ADDR)")
+	fs.StringVar(&cfg.geoIPDB, "geoip-db", envString("MDTHK_GEOIP_DB", ""),
+		"MaxMind country database to derive the client region (MDTHK_GEOIP_DB)")
+
+	lists := []struct {
+		val   *[]string
+		name  string
+		env   string
+		def   []string
+		usage string
+	}{
+		{&cfg.cors.AllowedOrigins, "cors-origins", "MDTHK_CORS_ORIGINS", nil,
+			"comma separated origins allowed to call the API, * for any, empty disables CORS"},
+		{&cfg.cors.AllowedMethods, "cors-methods", "MDTHK_CORS_METHODS",
+			service.DefaultCORSMethods, "comma separated methods allowed by CORS"},
+		{&cfg.cors.AllowedHeaders, "cors-headers", "MDTHK_CORS_HEADERS",
+			service.DefaultCORSHeaders, "comma separated request headers allowed by CORS"},
+		{&cfg.cors.ExposedHeaders, "cors-exposed-headers", "MDTHK_CORS_EXPOSED_HEADERS",
+			service.DefaultCORSExposedHeaders, "comma separated response headers exposed by CORS"},
+	}
+	for _, l := range lists {
+		*l.val = splitList(envString(l.env, strings.Join(l.def, ",")))
+		fs.Func(l.name, fmt.Sprintf("%s (%s)", l.usage, l.env), func(val *[]string) func(string) error {
+			return func(s string) error {
+				*val = splitList(s)
+				return nil
+			}
+		}(l.val))
+	}
+
+	durations := []struct {
+		val   *time.Duration
+		name  string
+		env   string
+		def   time.Duration
+		usage string
+	}{
+		{&cfg.readTimeout, "read-timeout", "MDTHK_READ_TIMEOUT", 10 * time.Second,
+			"maximum duration for reading a request"},
+		{&cfg.writeTimeout, "write-timeout", "MDTHK_WRITE_TIMEOUT", 5 * time.Minute,
+			"maximum duration for writing a response, e.g. the whole catalog"},
+		{&cfg.idleTimeout, "idle-timeout", "MDTHK_IDLE_TIMEOUT", 2 * time.Minute,
+			"maximum duration of idle keep-alive connections"},
+		{&cfg.shutdownTimeout, "shutdown-timeout", "MDTHK_SHUTDOWN_TIMEOUT", 30 * time.Second,
+			"maximum duration for finishing open requests on shutdown"},
+		{&cfg.importInterval, "import-interval", "MDTHK_IMPORT_INTERVAL", time.Hour,
+			"expected interval of catalog imports, used to report staleness"},
+		{&cfg.cors.MaxAge, "cors-max-age", "MDTHK_CORS_MAX_AGE", service.DefaultCORSMaxAge,
+			"time browsers may cache CORS preflight responses"},
+	}
+	for _, d := range durations {
+		def, err := envDuration(d.env, d.def)
+		if err != nil {
+			return cfg, err
+		}
+		fs.DurationVar(d.val, d.name, def, fmt.Sprintf("%s (%s)", d.usage, d.env))
+	}
+
+	bools := []struct {
+		val   *bool
+		name  string
+		env   string
+		usage string
+	}{
+		{&cfg.requireAPIKey, "require-api-key", "MDTHK_REQUIRE_API_KEY",
+			"reject requests without API key"},
+		{&cfg.trustProxy, "trust-proxy", "MDTHK_TRUST_PROXY",
+			"take the client IP from X-Forwarded-For, only behind a proxy"},
+	}
+	for _, b := range bools {
+		def, err := envBool(b.env)
+		if err != nil {
+			return cfg, err
+		}
+		fs.BoolVar(b.val, b.name, def, fmt.Sprintf("%s (%s)", b.usage, b.env))
+	}
+
+	rates := []struct {
+		val   *float64
+		name  string
+		env   string
+		def   float64
+		usage string
+	}{
+		{&cfg.searchRate, "search-rate", "MDTHK_SEARCH_RATE",
+			service.DefaultSearchQuota.Rate, "searches per minute and IP"},
+		{&cfg.bulkRate, "bulk-rate", "MDTHK_BULK_RATE",
+			service.DefaultBulkQuota.Rate, "catalog downloads per minute and IP"},
+		{&cfg.keySearchRate, "key-search-rate", "MDTHK_KEY_SEARCH_RATE",
+			service.DefaultKeySearchQuota.Rate, "searches per minute and API key"},
+		{&cfg.keyBulkRate, "key-bulk-rate", "MDTHK_KEY_BULK_RATE",
+			service.DefaultKeyBulkQuota.Rate, "catalog downloads per minute and API key"},
+	}
+	for _, r := range rates {
+		def, err := envFloat(r.env, r.def)
+		if err != nil {
+			return cfg, err
+		}
+		fs.Float64Var(r.val, r.name, def,
+			fmt.Sprintf("%s, negative for no limit (%s)", r.usage, r.env))
+	}
+
+	if err = fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if cfg.dsn == "" {
+		return cfg, fmt.Errorf("no DSN given, set -dsn or MDTHK_DSN")
+	}
+
+	return cfg, nil
+}
+
+func envString(name, def string) string {
+	if val, ok := os.LookupEnv(name); ok {
+		return val
+	}
+	return def
+}
+
+func envDuration(name string, def time.Duration) (time.Duration, error) {
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q in %s", val, name)
+	}
+
+	return d, nil
+}
+
+func envBool(name string) (bool, error) {
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q in %s", val, name)
+	}
+
+	return b, nil
+}
+
+func envFloat(name string, def float64) (float64, error) {
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return def, nil
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q in %s", val, name)
+	}
+
+	return f, nil
+}
+
+// splitList splits a comma separated list, empty elements are dropped.
+func splitList(s string) []string {
+	var result []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			result = append(result, e)
+		}
+	}
+	return result
+}
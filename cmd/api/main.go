@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/tschokko/mdthk-api/pkg/apikey"
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/geoip"
+	"github.com/tschokko/mdthk-api/pkg/savedsearch"
+	"github.com/tschokko/mdthk-api/pkg/service"
+	"github.com/urfave/negroni"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	cfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := sql.Open("postgres", cfg.dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "mdthk"))
+
+	store := catalog.NewStore(db)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt,
+		syscall.SIGTERM)
+	defer stop()
+
+	// Without GeoIP database the region is only known if passed by the client
+	var geoIP service.CountryResolver
+	if cfg.geoIPDB != "" {
+		countries, err := geoip.Open(cfg.geoIPDB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer countries.Close()
+		geoIP = countries
+	}
+
+	svc := service.New(store, apikey.NewStore(db), service.Options{
+		ImportInterval: cfg.importInterval,
+		RequireAPIKey:  cfg.requireAPIKey,
+		TrustProxy:     cfg.trustProxy,
+		SearchQuota:    service.Quota{Rate: cfg.searchRate, Burst: service.DefaultSearchQuota.Burst},
+		BulkQuota:      service.Quota{Rate: cfg.bulkRate, Burst: service.DefaultBulkQuota.Burst},
+		KeySearchQuota: service.Quota{Rate: cfg.keySearchRate, Burst: service.DefaultKeySearchQuota.Burst},
+		KeyBulkQuota:   service.Quota{Rate: cfg.keyBulkRate, Burst: service.DefaultKeyBulkQuota.Burst},
+		CORS:           cfg.cors,
+		GeoIP:          geoIP,
+		SavedSearches:  savedsearch.NewStore(db),
+	})
+
+	// The gRPC service runs next to the REST API on a separate port and shares
+	// its API keys and rate limits
+	var grpcServer *grpc.Server
+	if cfg.grpcAddr != "" {
+		lis, err := net.Listen("tcp", cfg.grpcAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grpcServer = grpc.NewServer(
+			grpc.ChainUnaryInterceptor(svc.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(svc.StreamInterceptor()))
+		catalog.NewGRPCServer(store).Register(grpcServer)
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Println("api: gRPC server failed:", err)
+				stop()
+			}
+		}()
+	}
+
+	n := negroni.New(negroni.NewRecovery(), negroni.NewLogger(), svc.Metrics(),
+		svc.CORS(), svc.Auth(), svc.Validation())
+	n.UseHandler(svc)
+
+	httpServer := &http.Server{
+		Addr:         cfg.httpAddr,
+		Handler:      n,
+		ReadTimeout:  cfg.readTimeout,
+		WriteTimeout: cfg.writeTimeout,
+		IdleTimeout:  cfg.idleTimeout,
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			log.Println("api: HTTP server failed:", err)
+			stop()
+		}
+	}()
+
+	log.Printf("api: serving REST on %s and gRPC on %s", cfg.httpAddr, cfg.grpcAddr)
+	<-ctx.Done()
+	log.Println("api: shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(),
+		cfg.shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Println("api: HTTP shutdown failed:", err)
+	}
+
+	// Long running streams would delay a graceful stop indefinitely, so they
+	// are cut off once the shutdown timeout expires
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			log.Println("api: gRPC shutdown timed out, closing open streams")
+			grpcServer.Stop()
+		}
+	}
+}
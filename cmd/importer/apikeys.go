@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/apikey"
+)
+
+var apiKeyCommands = map[string]func(cfg *config, args []string) error{
+	"create": runAPIKeyCreate,
+	"list":   runAPIKeyList,
+	"revoke": runAPIKeyRevoke,
+}
+
+func runAPIKey(cfg *config, args []string) error {
+	if len(args) == 0 {
+		return usageError("api-key expects a subcommand: create, list or revoke")
+	}
+
+	cmd, ok := apiKeyCommands[args[0]]
+	if !ok {
+		return usageError("unknown api-key subcommand %q", args[0])
+	}
+
+	return cmd(cfg, args[1:])
+}
+
+func runAPIKeyCreate(cfg *config, args []string) error {
+	fs := newFlagSet("api-key create", cfg)
+	searchRate := fs.Float64("search-rate", 0, "searches per minute, 0 for the default quota")
+	bulkRate := fs.Float64("bulk-rate", 0, "catalog downloads per minute, 0 for the default quota")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("api-key create expects exactly one name")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	key, secret, err := apikey.NewStore(db).Create(fs.Arg(0), *searchRate, *bulkRate)
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		return printJSON(struct {
+			apikey.Key
+			Secret string `json:"secret"`
+		}{key, secret})
+	}
+
+	fmt.Printf("Created API key %d for %s\n", key.ID, key.Name)
+	fmt.Printf("Secret: %s\n", secret)
+	fmt.Println("The secret can't be shown again.")
+
+	return nil
+}
+
+func runAPIKeyList(cfg *config, args []string) error {
+	fs := newFlagSet("api-key list", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("api-key list expects no arguments")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	keys, err := apikey.NewStore(db).FindAll()
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		if keys == nil {
+			keys = []apikey.Key{}
+		}
+		return printJSON(keys)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tPREFIX\tSEARCH RATE\tBULK RATE\tCREATED\tREVOKED")
+	for _, key := range keys {
+		revoked := ""
+		if key.RevokedAt != nil {
+			revoked = key.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name,
+			key.Prefix, formatRate(key.SearchRate), formatRate(key.BulkRate),
+			key.CreatedAt.Format(time.RFC3339), revoked)
+	}
+
+	return tw.Flush()
+}
+
+func runAPIKeyRevoke(cfg *config, args []string) error {
+	fs := newFlagSet("api-key revoke", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("api-key revoke expects exactly one ID")
+	}
+
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return usageError("invalid API key ID %q", fs.Arg(0))
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = apikey.NewStore(db).Revoke(id)
+	if err == apikey.ErrKeyNotFound {
+		return &exitError{code: exitNotFound, err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		return printJSON(struct {
+			ID int64 `json:"id"`
+		}{id})
+	}
+
+	fmt.Printf("Revoked API key %d\n", id)
+
+	return nil
+}
+
+func formatRate(rate float64) string {
+	if rate == 0 {
+		return "default"
+	}
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "/min"
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/importer"
+	pb "github.com/tschokko/mdthk-api/pkg/moviecat"
+)
+
+func runImport(cfg *config, args []string) error {
+	fs := newFlagSet("import", cfg)
+	force := fs.Bool("force", false, "import even if the movie list exists, unless it is current")
+	activate := fs.Bool("activate", true, "make the imported movie list current")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("import expects exactly one source")
+	}
+
+	raw, err := importer.ReadMovieList(context.Background(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := importer.ImportMovieList(db, raw, *force)
+	if err != nil {
+		return err
+	}
+
+	if *activate {
+		switched, err := importer.ActivateCatalog(db, result.Hash)
+		if err != nil {
+			return err
+		}
+		if switched {
+			notifyActivated(context.Background(), cfg, db, result.Hash)
+		}
+	}
+
+	if cfg.json {
+		return printJSON(result)
+	}
+
+	if result.Skipped {
+		fmt.Printf("Movie list %s already imported\n", result.Hash)
+	} else {
+		printResult(result)
+	}
+	if *activate {
+		fmt.Printf("Catalog %s is current\n", result.Hash)
+	}
+
+	return nil
+}
+
+func runDownload(cfg *config, args []string) error {
+	fs := newFlagSet("download", cfg)
+	out := fs.String("o", "", "output file, - for stdout (default: name of the list)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return usageError("download expects at most one URL")
+	}
+
+	src := importer.DefaultMovieListURL
+	if fs.NArg() == 1 {
+		src = fs.Arg(0)
+	}
+
+	if *out == "" {
+		*out = path.Base(src)
+	}
+
+	w, closeFn, err := createOutput(*out)
+	if err != nil {
+		return err
+	}
+
+	n, err := importer.DownloadMovieList(context.Background(), src, w)
+	if cerr := closeFn(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		return printJSON(struct {
+			URL  string `json:"url"`
+			File string `json:"file"`
+			Size int64  `json:"size"`
+		}{src, *out, n})
+	}
+
+	if *out != "-" {
+		fmt.Printf("Downloaded %s to %s (%d bytes)\n", src, *out, n)
+	}
+
+	return nil
+}
+
+func runListCatalogs(cfg *config, args []string) error {
+	fs := newFlagSet("list-catalogs", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("list-catalogs expects no arguments")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	catalogs, err := catalog.NewStore(db).FindAllCatalogs(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		if catalogs == nil {
+			catalogs = []catalog.Catalog{}
+		}
+		return printJSON(catalogs)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "HASH\tVERSION\tPUBLISHED\tIMPORTED\tMOVIES\tCURRENT")
+	for _, cat := range catalogs {
+		current := ""
+		if cat.IsCurrent {
+			current = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%d\t%s\n", cat.Hash, cat.Version,
+			cat.PublishedAt.Format(time.RFC3339),
+			cat.ImportedAt.Format(time.RFC3339), cat.MoviesCount, current)
+	}
+
+	return tw.Flush()
+}
+
+func runActivate(cfg *config, args []string) error {
+	fs := newFlagSet("activate", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("activate expects exactly one hash")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switched, err := importer.ActivateCatalog(db, fs.Arg(0))
+	if err == importer.ErrCatalogNotFound {
+		return &exitError{code: exitNotFound, err: err}
+	}
+	if err != nil {
+		return err
+	}
+	if switched {
+		notifyActivated(context.Background(), cfg, db, fs.Arg(0))
+	}
+
+	if cfg.json {
+		return printJSON(struct {
+			Hash string `json:"hash"`
+		}{fs.Arg(0)})
+	}
+
+	fmt.Printf("Catalog %s is current\n", fs.Arg(0))
+
+	return nil
+}
+
+func runGC(cfg *config, args []string) error {
+	fs := newFlagSet("gc", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("gc expects no arguments")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	dropped, err := importer.CollectGarbage(db)
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		if dropped == nil {
+			dropped = []string{}
+		}
+		return printJSON(struct {
+			Dropped []string `json:"dropped"`
+		}{dropped})
+	}
+
+	for _, hash := range dropped {
+		fmt.Printf("Dropped catalog %s\n", hash)
+	}
+
+	return nil
+}
+
+func runVerify(cfg *config, args []string) error {
+	fs := newFlagSet("verify", cfg)
+	strict := fs.Bool("strict", false, "fail if malformed values are found")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("verify expects exactly one source")
+	}
+
+	raw, err := importer.ReadMovieList(context.Background(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	result, err := importer.VerifyMovieList(raw)
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		err = printJSON(result)
+	} else {
+		printResult(result)
+		for _, d := range result.Diagnostics {
+			fmt.Println(d)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if *strict && len(result.Diagnostics) > 0 {
+		return &exitError{
+			code: exitInvalid,
+			err:  fmt.Errorf("%d malformed values found", len(result.Diagnostics)),
+		}
+	}
+
+	return nil
+}
+
+func runExport(cfg *config, args []string) error {
+	fs := newFlagSet("export", cfg)
+	out := fs.String("o", "moviecat.dat", "output file, - for stdout")
+	compact := fs.Bool("compact", false, "store the movie URLs in the compact form")
+	chunkSize := fs.Int("chunk-size", pb.DefaultChunkSize, "movie entries per chunk")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return usageError("export expects at most one hash")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	store := catalog.NewStore(db)
+
+	var cat catalog.Catalog
+	if fs.NArg() == 1 {
+		cat, err = store.FindCatalog(ctx, fs.Arg(0))
+	} else {
+		cat, err = store.FindCurrentCatalog(ctx)
+	}
+	if err == catalog.ErrCatalogNotFound || err == catalog.ErrNoCurrentCatalog {
+		return &exitError{code: exitNotFound, err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	w, closeFn, err := createOutput(*out)
+	if err != nil {
+		return err
+	}
+
+	enc := pb.NewEncoder(w)
+	enc.SetChunkSize(*chunkSize)
+	err = store.ExportCatalog(ctx, cat, enc, catalog.ExportOptions{CompactURLs: *compact})
+	if cerr := closeFn(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if cfg.json && *out != "-" {
+		return printJSON(struct {
+			Hash string `json:"hash"`
+			File string `json:"file"`
+		}{cat.Hash, *out})
+	}
+
+	if *out != "-" {
+		fmt.Printf("Exported catalog %s to %s\n", cat.Hash, *out)
+	}
+
+	return nil
+}
+
+// createOutput opens the output file. The name "-" refers to stdout, which
+// isn't closed by the returned function.
+func createOutput(name string) (io.Writer, func() error, error) {
+	if name == "-" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, f.Close, nil
+}
+
+func printResult(result importer.Result) {
+	fmt.Printf("Hash:        %s\n", result.Hash)
+	fmt.Printf("Version:     %s\n", result.Version)
+	fmt.Printf("Published:   %s\n", result.PublishedAt.Format(time.RFC3339))
+	fmt.Printf("Channels:    %d\n", result.ChannelsCount)
+	fmt.Printf("Topics:      %d\n", result.TopicsCount)
+	fmt.Printf("Movies:      %d\n", result.MoviesCount)
+	fmt.Printf("Diagnostics: %d\n", len(result.Diagnostics))
+}
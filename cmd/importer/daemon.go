@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tschokko/mdthk-api/pkg/importer"
+)
+
+func runDaemon(cfg *config, args []string) error {
+	fs := newFlagSet("daemon", cfg)
+	interval := fs.Duration("interval", time.Hour, "poll interval")
+	mirrors := fs.String("mirrors", strings.Join(importer.DefaultMirrors, ","),
+		"comma separated list of movie list URLs")
+	statusAddr := fs.String("status-addr", ":8090",
+		"address serving the status of the last run and the metrics, empty to disable")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("daemon expects no arguments")
+	}
+	if *interval <= 0 {
+		return usageError("interval must be positive")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt,
+		syscall.SIGTERM)
+	defer stop()
+
+	d := importer.NewDaemon(db, strings.Split(*mirrors, ","), *interval)
+	d.OnActivate(func(ctx context.Context, hash string) {
+		notifyActivated(ctx, cfg, db, hash)
+	})
+
+	if *statusAddr != "" {
+		prometheus.MustRegister(collectors.NewDBStatsCollector(db, "mdthk"))
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(d.Status())
+		})
+		mux.Handle("/metrics", promhttp.Handler())
+
+		srv := &http.Server{
+			Addr:         *statusAddr,
+			Handler:      mux,
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+				log.Println("importer: status server failed:", err)
+			}
+		}()
+		defer srv.Close()
+	}
+
+	log.Printf("importer: polling every %s", *interval)
+	err = d.Run(ctx)
+	if err == context.Canceled {
+		return nil
+	}
+
+	return err
+}
@@ -1,9 +1,194 @@
 package main
 
 import (
+	"database/sql"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"os"
+
+	_ "github.com/lib/pq"
+	"github.com/tschokko/mdthk-api/pkg/savedsearch"
+)
+
+// Exit codes of the importer
+const (
+	exitOK       = 0
+	exitFailure  = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitInvalid  = 4
 )
 
+const usage = `Usage: importer [-dsn DSN] [-json] <command> [arguments]
+
+Commands:
+  import [-force] [-activate=false] <file|url|->
+                       import a movie list and make it the current catalog
+  download [-o file] [url]
+                       download the movie list without importing it
+  list-catalogs        list all imported catalogs
+  activate <hash>      make the given catalog the current one
+  gc                   drop all catalogs except the current one
+  verify [-strict] <file|url|->
+                       parse a movie list and print a diagnostics report
+  export [-o file] [-compact] [-chunk-size n] [hash]
+                       export a catalog as length-delimited protobuf stream
+  daemon [-interval d] [-mirrors urls] [-status-addr addr]
+                       poll the mirrors and import new movie lists
+  api-key create [-search-rate r] [-bulk-rate r] <name>
+                       issue an API key, the secret is printed only once
+  api-key list         list all API keys
+  api-key revoke <id>  revoke an API key
+  webhook add [-secret s] <url>
+                       register a webhook notified after each catalog switch
+  webhook list         list all webhooks
+  webhook remove <id>  remove a webhook and its deliveries
+  webhook deliveries [-failed] [-n count]
+                       show the most recent deliveries
+  search add [-q text] [-channel id] [-topic id] [-min-duration s]
+      [-max-duration s] [-notify feed|webhook|email] [-target t] <name>
+                       save a search matched against each import's new movies
+  search list          list all saved searches
+  search remove <id>   remove a saved search and its matches
+  search matches [-n count] <id>
+                       show the most recent matches of a saved search
+
+The DSN defaults to the MDTHK_DSN environment variable. Email notifications
+of saved searches are sent via the SMTP server MDTHK_SMTP_ADDR (host:port)
+from MDTHK_SMTP_FROM, authenticated by MDTHK_SMTP_USER and
+MDTHK_SMTP_PASSWORD if set.
+
+Exit codes:
+  0  success
+  1  failure
+  2  invalid usage
+  3  catalog, API key, webhook or saved search not found
+  4  verify -strict found malformed values
+`
+
+// config holds the global options, which may be given before or after the
+// command.
+type config struct {
+	dsn  string
+	json bool
+	smtp savedsearch.SMTPConfig
+}
+
+// exitError carries the exit code for a failed command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func usageError(format string, a ...interface{}) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, a...)}
+}
+
+var commands = map[string]func(cfg *config, args []string) error{
+	"import":        runImport,
+	"download":      runDownload,
+	"list-catalogs": runListCatalogs,
+	"activate":      runActivate,
+	"gc":            runGC,
+	"verify":        runVerify,
+	"export":        runExport,
+	"daemon":        runDaemon,
+	"api-key":       runAPIKey,
+	"webhook":       runWebhook,
+	"search":        runSearch,
+}
+
 func main() {
-	fmt.Print("MV-Importer")
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	cfg := &config{
+		dsn: os.Getenv("MDTHK_DSN"),
+		smtp: savedsearch.SMTPConfig{
+			Addr:     os.Getenv("MDTHK_SMTP_ADDR"),
+			From:     os.Getenv("MDTHK_SMTP_FROM"),
+			Username: os.Getenv("MDTHK_SMTP_USER"),
+			Password: os.Getenv("MDTHK_SMTP_PASSWORD"),
+		},
+	}
+
+	fs := newFlagSet("importer", cfg)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return exitUsage
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "importer: unknown command %q\n\n%s", fs.Arg(0), usage)
+		return exitUsage
+	}
+
+	err := cmd(cfg, fs.Args()[1:])
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "importer: %s\n", err)
+	if e, ok := err.(*exitError); ok {
+		return e.code
+	}
+	return exitFailure
+}
+
+// newFlagSet creates a flag set containing the global options. Parse errors
+// are returned and not handled by the flag set itself.
+func newFlagSet(name string, cfg *config) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	fs.StringVar(&cfg.dsn, "dsn", cfg.dsn, "PostgreSQL connection string")
+	fs.BoolVar(&cfg.json, "json", cfg.json, "print the output as JSON")
+	return fs
+}
+
+// parseFlags parses the command arguments. Parse errors are mapped to usage
+// errors.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return &exitError{code: exitUsage, err: err}
+	}
+	return nil
+}
+
+// openDB opens the database configured by the DSN and checks the connection.
+func openDB(cfg *config) (*sql.DB, error) {
+	if cfg.dsn == "" {
+		return nil, usageError("no DSN given, set -dsn or MDTHK_DSN")
+	}
+
+	db, err := sql.Open("postgres", cfg.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// printJSON writes v as indented JSON to stdout.
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
 }
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/savedsearch"
+	"github.com/tschokko/mdthk-api/pkg/webhook"
+)
+
+var searchCommands = map[string]func(cfg *config, args []string) error{
+	"add":     runSearchAdd,
+	"list":    runSearchList,
+	"remove":  runSearchRemove,
+	"matches": runSearchMatches,
+}
+
+func runSearch(cfg *config, args []string) error {
+	if len(args) == 0 {
+		return usageError("search expects a subcommand: add, list, remove or matches")
+	}
+
+	cmd, ok := searchCommands[args[0]]
+	if !ok {
+		return usageError("unknown search subcommand %q", args[0])
+	}
+
+	return cmd(cfg, args[1:])
+}
+
+func runSearchAdd(cfg *config, args []string) error {
+	fs := newFlagSet("search add", cfg)
+	query := fs.String("q", "", "text matched against title and topic")
+	channel := fs.Int64("channel", 0, "channel ID")
+	topic := fs.Int64("topic", 0, "topic ID")
+	minDuration := fs.Int64("min-duration", 0, "minimum duration in seconds")
+	maxDuration := fs.Int64("max-duration", 0, "maximum duration in seconds")
+	notify := fs.String("notify", savedsearch.NotifyFeed,
+		"notifier: feed, webhook or email")
+	target := fs.String("target", "", "webhook URL or email address")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("search add expects exactly one name")
+	}
+	if *query == "" && *channel == 0 && *topic == 0 && *minDuration == 0 &&
+		*maxDuration == 0 {
+		return usageError("search add expects at least one filter")
+	}
+
+	switch *notify {
+	case savedsearch.NotifyFeed:
+		if *target != "" {
+			return usageError("feed notifier expects no target")
+		}
+	case savedsearch.NotifyWebhook:
+		u, err := url.Parse(*target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return usageError("invalid webhook URL %q", *target)
+		}
+	case savedsearch.NotifyEmail:
+		addr, err := mail.ParseAddress(*target)
+		if err != nil {
+			return usageError("invalid email address %q", *target)
+		}
+		*target = addr.Address
+	default:
+		return usageError("unknown notifier %q", *notify)
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	search, secret, err := savedsearch.NewStore(db).Create(savedsearch.Search{
+		Name:        fs.Arg(0),
+		Query:       *query,
+		ChannelID:   *channel,
+		TopicID:     *topic,
+		MinDuration: *minDuration,
+		MaxDuration: *maxDuration,
+		Notifier:    *notify,
+		Target:      *target,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		if search.Notifier != savedsearch.NotifyWebhook {
+			return printJSON(search)
+		}
+		return printJSON(struct {
+			savedsearch.Search
+			Secret string `json:"secret"`
+		}{search, secret})
+	}
+
+	fmt.Printf("Added saved search %d %q\n", search.ID, search.Name)
+	fmt.Printf("Feed: /feeds/searches/%s.atom\n", search.FeedToken)
+	if search.Notifier == savedsearch.NotifyWebhook {
+		fmt.Printf("Secret: %s\n", secret)
+		fmt.Printf("Payloads are signed with HMAC-SHA256 in the %s header.\n",
+			webhook.SignatureHeader)
+	}
+
+	return nil
+}
+
+func runSearchList(cfg *config, args []string) error {
+	fs := newFlagSet("search list", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("search list expects no arguments")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	searches, err := savedsearch.NewStore(db).FindAll()
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		if searches == nil {
+			searches = []savedsearch.Search{}
+		}
+		return printJSON(searches)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tQUERY\tNOTIFIER\tTARGET\tFEED TOKEN\tCREATED")
+	for _, s := range searches {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", s.ID, s.Name, s.Query,
+			s.Notifier, s.Target, s.FeedToken, s.CreatedAt.Format(time.RFC3339))
+	}
+
+	return tw.Flush()
+}
+
+func runSearchRemove(cfg *config, args []string) error {
+	fs := newFlagSet("search remove", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("search remove expects exactly one ID")
+	}
+
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return usageError("invalid saved search ID %q", fs.Arg(0))
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = savedsearch.NewStore(db).Delete(id)
+	if err == savedsearch.ErrSearchNotFound {
+		return &exitError{code: exitNotFound, err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		return printJSON(struct {
+			ID int64 `json:"id"`
+		}{id})
+	}
+
+	fmt.Printf("Removed saved search %d\n", id)
+
+	return nil
+}
+
+func runSearchMatches(cfg *config, args []string) error {
+	fs := newFlagSet("search matches", cfg)
+	limit := fs.Int("n", 20, "number of matches to show")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("search matches expects exactly one ID")
+	}
+
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return usageError("invalid saved search ID %q", fs.Arg(0))
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	matches, err := savedsearch.NewStore(db).FindMatches(id, *limit)
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		type match struct {
+			CatalogHash string    `json:"catalogHash"`
+			MatchedAt   time.Time `json:"matchedAt"`
+			StableID    string    `json:"stableId"`
+			Topic       string    `json:"topic"`
+			Title       string    `json:"title"`
+			PublishedAt time.Time `json:"publishedAt"`
+			URL         string    `json:"url"`
+		}
+		result := []match{}
+		for _, m := range matches {
+			result = append(result, match{m.CatalogHash, m.MatchedAt,
+				m.Movie.StableID, m.Movie.Topic, m.Movie.Title,
+				m.Movie.PublishedAt, m.Movie.URL})
+		}
+		return printJSON(result)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "MATCHED\tCATALOG\tPUBLISHED\tTOPIC\tTITLE")
+	for _, m := range matches {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.MatchedAt.Format(time.RFC3339),
+			m.CatalogHash, m.Movie.PublishedAt.Format(time.RFC3339),
+			m.Movie.Topic, m.Movie.Title)
+	}
+
+	return tw.Flush()
+}
+
+// evaluateSearches evaluates the saved searches against the movies added by
+// the catalog which became current. Email notifications are only sent if an
+// SMTP server is configured.
+func evaluateSearches(ctx context.Context, cfg *config, db *sql.DB, hash string) {
+	evaluator := savedsearch.NewEvaluator(savedsearch.NewStore(db),
+		catalog.NewStore(db))
+	evaluator.Register(savedsearch.NotifyWebhook, savedsearch.NewWebhookNotifier(
+		webhook.NewNotifier(webhook.NewStore(db), catalog.NewStore(db), nil)))
+	if cfg.smtp.Addr != "" {
+		email, err := savedsearch.NewEmailNotifier(cfg.smtp)
+		if err != nil {
+			log.Printf("importer: email notifications disabled: %s", err)
+		} else {
+			evaluator.Register(savedsearch.NotifyEmail, email)
+		}
+	}
+
+	if err := evaluator.CatalogActivated(ctx, hash); err != nil {
+		log.Printf("importer: failed to evaluate saved searches: %s", err)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/webhook"
+)
+
+var webhookCommands = map[string]func(cfg *config, args []string) error{
+	"add":        runWebhookAdd,
+	"list":       runWebhookList,
+	"remove":     runWebhookRemove,
+	"deliveries": runWebhookDeliveries,
+}
+
+func runWebhook(cfg *config, args []string) error {
+	if len(args) == 0 {
+		return usageError("webhook expects a subcommand: add, list, remove or deliveries")
+	}
+
+	cmd, ok := webhookCommands[args[0]]
+	if !ok {
+		return usageError("unknown webhook subcommand %q", args[0])
+	}
+
+	return cmd(cfg, args[1:])
+}
+
+func runWebhookAdd(cfg *config, args []string) error {
+	fs := newFlagSet("webhook add", cfg)
+	secret := fs.String("secret", "", "secret signing the payloads (default: random)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("webhook add expects exactly one URL")
+	}
+
+	u, err := url.Parse(fs.Arg(0))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return usageError("invalid webhook URL %q", fs.Arg(0))
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	hook, secretUsed, err := webhook.NewStore(db).Create(u.String(), *secret)
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		return printJSON(struct {
+			webhook.Webhook
+			Secret string `json:"secret"`
+		}{hook, secretUsed})
+	}
+
+	fmt.Printf("Added webhook %d for %s\n", hook.ID, hook.URL)
+	fmt.Printf("Secret: %s\n", secretUsed)
+	fmt.Printf("Payloads are signed with HMAC-SHA256 in the %s header.\n",
+		webhook.SignatureHeader)
+
+	return nil
+}
+
+func runWebhookList(cfg *config, args []string) error {
+	fs := newFlagSet("webhook list", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("webhook list expects no arguments")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	hooks, err := webhook.NewStore(db).FindAll()
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		if hooks == nil {
+			hooks = []webhook.Webhook{}
+		}
+		return printJSON(hooks)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tURL\tCREATED")
+	for _, hook := range hooks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", hook.ID, hook.URL,
+			hook.CreatedAt.Format(time.RFC3339))
+	}
+
+	return tw.Flush()
+}
+
+func runWebhookRemove(cfg *config, args []string) error {
+	fs := newFlagSet("webhook remove", cfg)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("webhook remove expects exactly one ID")
+	}
+
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return usageError("invalid webhook ID %q", fs.Arg(0))
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = webhook.NewStore(db).Delete(id)
+	if err == webhook.ErrWebhookNotFound {
+		return &exitError{code: exitNotFound, err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		return printJSON(struct {
+			ID int64 `json:"id"`
+		}{id})
+	}
+
+	fmt.Printf("Removed webhook %d\n", id)
+
+	return nil
+}
+
+func runWebhookDeliveries(cfg *config, args []string) error {
+	fs := newFlagSet("webhook deliveries", cfg)
+	failed := fs.Bool("failed", false, "show only failed deliveries")
+	limit := fs.Int("n", 20, "number of deliveries to show")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("webhook deliveries expects no arguments")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	deliveries, err := webhook.NewStore(db).FindDeliveries(*failed, *limit)
+	if err != nil {
+		return err
+	}
+
+	if cfg.json {
+		if deliveries == nil {
+			deliveries = []webhook.Delivery{}
+		}
+		return printJSON(deliveries)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tWEBHOOK\tCATALOG\tATTEMPTS\tSTATUS\tCREATED\tRESULT")
+	for _, d := range deliveries {
+		result := "delivered"
+		if d.DeliveredAt == nil {
+			result = "failed: " + d.Error
+		}
+		status := ""
+		if d.StatusCode != 0 {
+			status = strconv.Itoa(d.StatusCode)
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%d\t%s\t%s\t%s\n", d.ID, d.WebhookID,
+			d.CatalogHash, d.Attempts, status, d.CreatedAt.Format(time.RFC3339),
+			result)
+	}
+
+	return tw.Flush()
+}
+
+// notifyActivated notifies the webhooks about the catalog which became
+// current and evaluates the saved searches. Failures are only logged, since
+// the catalog switch already happened.
+func notifyActivated(ctx context.Context, cfg *config, db *sql.DB, hash string) {
+	notifier := webhook.NewNotifier(webhook.NewStore(db), catalog.NewStore(db), nil)
+	if err := notifier.CatalogActivated(ctx, hash); err != nil {
+		log.Printf("importer: failed to notify webhooks: %s", err)
+	}
+
+	evaluateSearches(ctx, cfg, db, hash)
+}
@@ -0,0 +1,175 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// keyPrefix marks API keys issued by this service, which makes leaked keys
+// easy to spot.
+const keyPrefix = "mdthk_"
+
+// undefinedTable is the PostgreSQL error code of a missing table.
+const undefinedTable = "42P01"
+
+// ErrKeyNotFound is returned if an API key doesn't exist or was revoked.
+var ErrKeyNotFound = errors.New("API key not found")
+
+// Key describes an API key. Only the hash of the secret is stored, the secret
+// itself is returned once on creation. The rates are given in requests per
+// minute, zero selects the default quota of the service.
+type Key struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	SearchRate float64    `json:"searchRate,omitempty"`
+	BulkRate   float64    `json:"bulkRate,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// Store manages the API keys in the database.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new key store on top of the given database.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// createTable creates the table of the API keys if it doesn't exist.
+func (s *Store) createTable() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS api_keys (
+		id bigserial PRIMARY KEY,
+		name text NOT NULL,
+		prefix varchar(16) NOT NULL,
+		secret_hash char(64) NOT NULL UNIQUE,
+		search_rate double precision NOT NULL DEFAULT 0,
+		bulk_rate double precision NOT NULL DEFAULT 0,
+		created_at timestamptz NOT NULL DEFAULT now(),
+		revoked_at timestamptz
+	)`)
+	return err
+}
+
+// Create issues a new API key and returns it together with its secret.
+func (s *Store) Create(name string, searchRate, bulkRate float64) (Key, string, error) {
+	var result Key
+
+	err := s.createTable()
+	if err != nil {
+		return result, "", err
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return result, "", err
+	}
+	secret := keyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	err = s.db.QueryRow(
+		`INSERT INTO api_keys (name, prefix, secret_hash, search_rate, bulk_rate)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, name, prefix, search_rate, bulk_rate, created_at`,
+		name, secret[:len(keyPrefix)+4], hashSecret(secret), searchRate,
+		bulkRate).Scan(&result.ID, &result.Name, &result.Prefix,
+		&result.SearchRate, &result.BulkRate, &result.CreatedAt)
+	if err != nil {
+		return result, "", err
+	}
+
+	return result, secret, nil
+}
+
+// FindAll returns all keys including the revoked ones ordered by ID.
+func (s *Store) FindAll() ([]Key, error) {
+	var result []Key
+
+	err := s.createTable()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, name, prefix, search_rate, bulk_rate, created_at, revoked_at
+        FROM api_keys ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key Key
+		if err := rows.Scan(&key.ID, &key.Name, &key.Prefix, &key.SearchRate,
+			&key.BulkRate, &key.CreatedAt, &key.RevokedAt); err != nil {
+			return nil, err
+		}
+
+		result = append(result, key)
+	}
+
+	return result, rows.Err()
+}
+
+// Revoke revokes the key with the given ID. Revoking a key twice keeps the
+// time of the first revocation.
+func (s *Store) Revoke(id int64) error {
+	err := s.createTable()
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec(
+		`UPDATE api_keys SET revoked_at = COALESCE(revoked_at, now())
+        WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// Lookup returns the active key with the given secret. Lookups don't create
+// the table, so the API gets along with read-only database access. A missing
+// table means no key has been issued yet.
+func (s *Store) Lookup(secret string) (Key, error) {
+	var result Key
+
+	err := s.db.QueryRow(
+		`SELECT id, name, prefix, search_rate, bulk_rate, created_at
+        FROM api_keys WHERE secret_hash = $1 AND revoked_at IS NULL`,
+		hashSecret(secret)).Scan(&result.ID, &result.Name, &result.Prefix,
+		&result.SearchRate, &result.BulkRate, &result.CreatedAt)
+	if err == sql.ErrNoRows {
+		return result, ErrKeyNotFound
+	}
+	if e, ok := err.(*pq.Error); ok && e.Code == undefinedTable {
+		return result, ErrKeyNotFound
+	}
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
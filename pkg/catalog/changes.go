@@ -0,0 +1,188 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// undefinedTable is the PostgreSQL error code of a missing table.
+const undefinedTable = "42P01"
+
+// Kinds of changes, see Change
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change records a movie added, removed or changed by switching the current
+// catalog. The movie is identified by its stable ID.
+type Change struct {
+	ID           int64
+	Kind         string
+	StableID     string
+	CatalogHash  string
+	PreviousHash string
+	ChangedAt    time.Time
+	Channel      string
+	Topic        string
+	Title        string
+	PublishedAt  time.Time
+	Duration     int64
+	URL          string
+}
+
+// ChangeFilter restricts the changes returned by the store. Zero values don't
+// restrict anything.
+type ChangeFilter struct {
+	Since       time.Time
+	Kind        string
+	CatalogHash string
+	Limit       int
+	Offset      int
+}
+
+// ChangeSummary counts the changes recorded for a catalog by kind.
+type ChangeSummary struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+// ValidChangeKind checks if the kind of change is known. An empty kind is
+// valid and matches all changes.
+func ValidChangeKind(kind string) bool {
+	switch kind {
+	case "", ChangeAdded, ChangeRemoved, ChangeChanged:
+		return true
+	}
+	return false
+}
+
+// CountChanges returns the number of changes matching the filter. Limit and
+// offset of the filter are ignored.
+func (s *Store) CountChanges(ctx context.Context, filter ChangeFilter) (int, error) {
+	result := 0
+
+	where, args := changeFilterClause(filter)
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(id) FROM catalog_changes"+where,
+		args...).Scan(&result)
+	if isUndefinedTable(err) {
+		return 0, nil
+	}
+
+	return result, err
+}
+
+// FindChanges returns the changes matching the filter, oldest first.
+func (s *Store) FindChanges(ctx context.Context, filter ChangeFilter) ([]Change, error) {
+	var result []Change
+
+	where, args := changeFilterClause(filter)
+	sqlStmt := fmt.Sprintf(`SELECT id, kind, stable_id, catalog_hash,
+            previous_hash, changed_at, channel, topic, title, published_at,
+            duration, url
+        FROM catalog_changes%s ORDER BY changed_at, id`, where)
+
+	if filter.Limit > 0 {
+		sqlStmt = fmt.Sprintf("%s LIMIT %d", sqlStmt, filter.Limit)
+	}
+
+	if filter.Offset > 0 {
+		sqlStmt = fmt.Sprintf("%s OFFSET %d", sqlStmt, filter.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStmt, args...)
+	if isUndefinedTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c Change
+		if err := rows.Scan(&c.ID, &c.Kind, &c.StableID, &c.CatalogHash,
+			&c.PreviousHash, &c.ChangedAt, &c.Channel, &c.Topic, &c.Title,
+			&c.PublishedAt, &c.Duration, &c.URL); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+
+	return result, rows.Err()
+}
+
+// SummarizeChanges counts the changes recorded when the catalog with the
+// given hash became current.
+func (s *Store) SummarizeChanges(ctx context.Context, hash string) (ChangeSummary, error) {
+	var result ChangeSummary
+
+	rows, err := s.db.QueryContext(ctx, `SELECT kind, COUNT(id) FROM catalog_changes
+        WHERE catalog_hash = $1 GROUP BY kind`, hash)
+	if isUndefinedTable(err) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var count int
+		if err := rows.Scan(&kind, &count); err != nil {
+			return result, err
+		}
+		switch kind {
+		case ChangeAdded:
+			result.Added = count
+		case ChangeRemoved:
+			result.Removed = count
+		case ChangeChanged:
+			result.Changed = count
+		}
+	}
+
+	return result, rows.Err()
+}
+
+// changeFilterClause builds the SQL where clause and its arguments for the
+// given filter.
+func changeFilterClause(filter ChangeFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		conds = append(conds, fmt.Sprintf("changed_at > $%d", len(args)))
+	}
+
+	if filter.Kind != "" {
+		args = append(args, filter.Kind)
+		conds = append(conds, fmt.Sprintf("kind = $%d", len(args)))
+	}
+
+	if filter.CatalogHash != "" {
+		args = append(args, filter.CatalogHash)
+		conds = append(conds, fmt.Sprintf("catalog_hash = $%d", len(args)))
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// isUndefinedTable checks if the error is caused by a missing table, e.g.
+// because the change log hasn't been created yet.
+func isUndefinedTable(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == undefinedTable
+}
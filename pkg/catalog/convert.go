@@ -0,0 +1,108 @@
+package catalog
+
+import (
+	"strconv"
+
+	pb "github.com/tschokko/mdthk-api/pkg/moviecat"
+)
+
+func catalogToInfo(cat Catalog) *pb.CatalogInfo {
+	return &pb.CatalogInfo{
+		Version:       int32(cat.Version),
+		PublishedAt:   cat.PublishedAt.Unix(),
+		Md5Hash:       []byte(cat.Hash),
+		ChannelsCount: int64(cat.ChannelsCount),
+		TopicsCount:   int64(cat.TopicsCount),
+		MoviesCount:   int64(cat.MoviesCount),
+	}
+}
+
+func channelsToEntries(channels map[int64]string) []*pb.ChannelEntry {
+	var result []*pb.ChannelEntry
+
+	for id, name := range channels {
+		result = append(result, &pb.ChannelEntry{
+			Version: 1,
+			Id:      id,
+			Name:    name,
+		})
+	}
+
+	return result
+}
+
+func topicsToEntries(topics map[int64]string) []*pb.TopicEntry {
+	var result []*pb.TopicEntry
+
+	for id, name := range topics {
+		result = append(result, &pb.TopicEntry{
+			Version: 1,
+			Id:      id,
+			Name:    name,
+		})
+	}
+
+	return result
+}
+
+func movieToEntry(movie Movie) *pb.MovieEntry {
+	result := &pb.MovieEntry{
+		Version:        pb.FormatVersion,
+		Id:             strconv.FormatInt(movie.ID, 10),
+		ChannelId:      movie.ChannelID,
+		TopicId:        movie.TopicID,
+		Title:          movie.Title,
+		PublishedAt:    movie.PublishedAt.Unix(),
+		Url:            movie.URL,
+		Size:           movie.Size,
+		Descr:          movie.Descr,
+		Geo:            movie.Geo,
+		IsNew:          movie.IsNew,
+		Duration:       movie.Duration,
+		WebsiteUrl:     movie.WebsiteURL,
+		SubtitleUrl:    movie.SubTitleURL,
+		SmallFormatUrl: movie.SmallFormatURL,
+		HdFormatUrl:    movie.HDFormatURL,
+		HistoryUrl:     movie.HistoryURL,
+		UnixDate:       movie.UnixDate,
+		GeoRegions:     geoRegions(movie),
+
+		AudioDescription: movie.AudioDescription,
+		SignLanguage:     movie.SignLanguage,
+		OriginalVersion:  movie.OriginalVersion,
+		BaseId:           movie.BaseID,
+		StableId:         movie.StableID,
+	}
+
+	if movie.WebsiteURL != "" {
+		result.HasWebsiteUrl = true
+	}
+	if movie.SubTitleURL != "" {
+		result.HasSubtitleUrl = true
+	}
+	if movie.SmallFormatURL != "" {
+		result.HasSmallFormatUrl = true
+	}
+	if movie.HDFormatURL != "" {
+		result.HasHdFormatUrl = true
+	}
+	if movie.HistoryURL != "" {
+		result.HasHistoryUrl = true
+	}
+
+	return result
+}
+
+// geoRegions maps the normalized country codes of the movie to the regions of
+// the format. Movies without codes are available worldwide, which is only
+// reported as region if the movie list marked them explicitly.
+func geoRegions(movie Movie) []pb.GeoRegion {
+	if len(movie.GeoCodes) == 0 {
+		if pb.IsWorldwide(movie.Geo) {
+			return []pb.GeoRegion{pb.GeoRegion_GEO_REGION_WORLD}
+		}
+		return nil
+	}
+
+	return pb.GeoRegionsFromCodes(movie.GeoCodes)
+}
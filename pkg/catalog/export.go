@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"context"
+
+	pb "github.com/tschokko/mdthk-api/pkg/moviecat"
+)
+
+// ExportOptions controls the output of ExportCatalog.
+type ExportOptions struct {
+	// CompactURLs enables the MediathekView URL encoding, which stores the
+	// secondary URLs of a movie relative to its main URL.
+	CompactURLs bool
+}
+
+// ExportCatalog writes the given catalog to the encoder. The movies are read
+// from the database one by one and handed to the encoder, which writes them in
+// chunks. The encoder is closed afterwards, but not the underlying writer.
+func (s *Store) ExportCatalog(ctx context.Context, cat Catalog, enc *pb.Encoder, opts ExportOptions) error {
+	channels, err := s.FindAllChannels(ctx, cat.Hash)
+	if err != nil {
+		return err
+	}
+
+	topics, err := s.FindAllTopics(ctx, cat.Hash)
+	if err != nil {
+		return err
+	}
+
+	err = enc.EncodeHeader(&pb.MovieCatalogHeader{
+		Version:     pb.FormatVersion,
+		PublishedAt: cat.PublishedAt.Unix(),
+		Md5Hash:     []byte(cat.Hash),
+		Channels:    channelsToEntries(channels),
+		Topics:      topicsToEntries(topics),
+		MoviesCount: int64(cat.MoviesCount),
+		ListVersion: int32(cat.Version),
+		CompactUrls: opts.CompactURLs,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.EachMovie(ctx, cat.Hash, MovieFilter{}, func(movie Movie) error {
+		entry := movieToEntry(movie)
+		if opts.CompactURLs {
+			entry.CompactURLs()
+		}
+		return enc.EncodeMovie(entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
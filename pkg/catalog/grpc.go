@@ -0,0 +1,172 @@
+package catalog
+
+import (
+	"context"
+
+	pb "github.com/tschokko/mdthk-api/pkg/moviecat"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer implements the moviecat.MovieCatalogService on top of the
+// catalog store.
+type GRPCServer struct {
+	store *Store
+}
+
+// NewGRPCServer creates a new gRPC service instance for the given store.
+func NewGRPCServer(store *Store) *GRPCServer {
+	return &GRPCServer{store: store}
+}
+
+// Register registers the service on the given gRPC server.
+func (s *GRPCServer) Register(gs *grpc.Server) {
+	pb.RegisterMovieCatalogServiceServer(gs, s)
+}
+
+// GetCatalogInfo returns the meta data of the current catalog.
+func (s *GRPCServer) GetCatalogInfo(ctx context.Context, req *pb.GetCatalogInfoRequest) (*pb.CatalogInfo, error) {
+	cat, err := s.currentCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalogToInfo(cat), nil
+}
+
+// ListChannels returns all channels of the current catalog.
+func (s *GRPCServer) ListChannels(ctx context.Context, req *pb.ListChannelsRequest) (*pb.ListChannelsResponse, error) {
+	cat, err := s.currentCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := s.store.FindAllChannels(ctx, cat.Hash)
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+
+	return &pb.ListChannelsResponse{Channels: channelsToEntries(channels)}, nil
+}
+
+// ListTopics returns all topics of the current catalog.
+func (s *GRPCServer) ListTopics(ctx context.Context, req *pb.ListTopicsRequest) (*pb.ListTopicsResponse, error) {
+	cat, err := s.currentCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.store.FindAllTopics(ctx, cat.Hash)
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+
+	return &pb.ListTopicsResponse{Topics: topicsToEntries(topics)}, nil
+}
+
+// SearchMovies streams all movies of the current catalog matching the
+// request.
+func (s *GRPCServer) SearchMovies(req *pb.SearchMoviesRequest, stream pb.MovieCatalogService_SearchMoviesServer) error {
+	ctx := stream.Context()
+	cat, err := s.currentCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := MovieFilter{
+		Query:     req.GetQuery(),
+		ChannelID: req.GetChannelId(),
+		TopicID:   req.GetTopicId(),
+		Limit:     int(req.GetLimit()),
+		Offset:    int(req.GetOffset()),
+	}
+
+	err = s.store.EachMovie(ctx, cat.Hash, filter, func(movie Movie) error {
+		return stream.Send(movieToEntry(movie))
+	})
+	if err != nil {
+		return storeError(ctx, err)
+	}
+
+	return nil
+}
+
+// StreamCatalog streams the whole current catalog in chunks. The first chunk
+// carries the catalog info, channels and topics. All following chunks carry
+// up to chunk size movies.
+func (s *GRPCServer) StreamCatalog(req *pb.StreamCatalogRequest, stream pb.MovieCatalogService_StreamCatalogServer) error {
+	ctx := stream.Context()
+	cat, err := s.currentCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	chunkSize := int(req.GetChunkSize())
+	if chunkSize <= 0 {
+		chunkSize = pb.DefaultChunkSize
+	}
+
+	channels, err := s.store.FindAllChannels(ctx, cat.Hash)
+	if err != nil {
+		return storeError(ctx, err)
+	}
+
+	topics, err := s.store.FindAllTopics(ctx, cat.Hash)
+	if err != nil {
+		return storeError(ctx, err)
+	}
+
+	err = stream.Send(&pb.MovieCatalogChunk{
+		Info:     catalogToInfo(cat),
+		Channels: channelsToEntries(channels),
+		Topics:   topicsToEntries(topics),
+	})
+	if err != nil {
+		return err
+	}
+
+	chunk := &pb.MovieCatalogChunk{}
+	err = s.store.EachMovie(ctx, cat.Hash, MovieFilter{}, func(movie Movie) error {
+		chunk.Movies = append(chunk.Movies, movieToEntry(movie))
+		if len(chunk.Movies) < chunkSize {
+			return nil
+		}
+
+		err := stream.Send(chunk)
+		chunk = &pb.MovieCatalogChunk{}
+		return err
+	})
+	if err != nil {
+		return storeError(ctx, err)
+	}
+
+	if len(chunk.Movies) > 0 {
+		return stream.Send(chunk)
+	}
+
+	return nil
+}
+
+// currentCatalog fetches the current catalog and maps the store errors to
+// proper gRPC status errors.
+func (s *GRPCServer) currentCatalog(ctx context.Context) (Catalog, error) {
+	cat, err := s.store.FindCurrentCatalog(ctx)
+	if err == ErrNoCurrentCatalog {
+		return cat, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return cat, storeError(ctx, err)
+	}
+
+	return cat, nil
+}
+
+// storeError maps errors of the store to gRPC status errors. Queries aborted
+// because the client went away or the deadline passed keep that status.
+func storeError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,269 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/tschokko/mdthk-api/pkg/moviecat"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testHash = "0123456789abcdef0123456789abcdef"
+
+var testPublishedAt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testMovies are the movies of the test catalog, in the column order of
+// EachMovie.
+var testMovies = [][]driver.Value{
+	testMovie(1, "Tatort", "Das Team"),
+	testMovie(2, "Tatort", "Der Fall"),
+	testMovie(3, "Sportschau", "Bundesliga"),
+}
+
+func testMovie(id int64, topic, title string) []driver.Value {
+	return []driver.Value{id, strings.Repeat("a", 31) + string(rune('0'+id)),
+		"", int64(1), int64(10), topic, title, testPublishedAt, int64(5400),
+		int64(800), "", "https://example.org/" + title + ".mp4", "", "", "", "",
+		testPublishedAt.Unix(), "", "", []byte("{DE}"), false, "", int64(0),
+		int64(0), int64(0), false, false, false, nil}
+}
+
+// fakeDriver answers the queries of the store from the test catalog. A
+// catalog without current entry can be simulated by the DSN "empty".
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return fakeConn{empty: name == "empty"}, nil
+}
+
+type fakeConn struct {
+	empty bool
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{conn: c, query: query}, nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+type fakeStmt struct {
+	conn  fakeConn
+	query string
+}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (fakeStmt) NumInput() int {
+	return -1
+}
+
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec not supported")
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	switch {
+	case strings.Contains(s.query, "FROM catalogs WHERE is_current"):
+		if s.conn.empty {
+			return &fakeRows{columns: 8}, nil
+		}
+		return &fakeRows{columns: 8, values: [][]driver.Value{{testHash,
+			int64(3), testPublishedAt, testPublishedAt, int64(1), int64(2),
+			int64(len(testMovies)), true}}}, nil
+	case strings.Contains(s.query, ".channels"):
+		return &fakeRows{columns: 2, values: [][]driver.Value{{int64(1), "ARD"}}}, nil
+	case strings.Contains(s.query, ".topics"):
+		return &fakeRows{columns: 2, values: [][]driver.Value{
+			{int64(10), "Tatort"}, {int64(11), "Sportschau"}}}, nil
+	case strings.Contains(s.query, "SELECT id, stable_id"):
+		rows := &fakeRows{columns: len(testMovies[0])}
+		for _, movie := range testMovies {
+			if len(args) == 1 && !strings.Contains(strings.ToLower(movie[6].(string)+movie[5].(string)),
+				strings.ToLower(strings.Trim(args[0].(string), "%"))) {
+				continue
+			}
+			rows.values = append(rows.values, movie)
+		}
+		return rows, nil
+	}
+
+	return nil, errors.New("unexpected query: " + s.query)
+}
+
+type fakeRows struct {
+	columns int
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return make([]string, r.columns)
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("catalogtest", fakeDriver{})
+}
+
+// newTestClient serves the gRPC service over an in-memory connection.
+func newTestClient(t *testing.T, dsn string) pb.MovieCatalogServiceClient {
+	t.Helper()
+
+	db, err := sql.Open("catalogtest", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	NewGRPCServer(NewStore(db)).Register(gs)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMovieCatalogServiceClient(conn)
+}
+
+func TestGRPCGetCatalogInfo(t *testing.T) {
+	client := newTestClient(t, "")
+
+	info, err := client.GetCatalogInfo(context.Background(), &pb.GetCatalogInfoRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(info.GetMd5Hash()) != testHash {
+		t.Errorf("hash = %q, want %q", info.GetMd5Hash(), testHash)
+	}
+	if info.GetMoviesCount() != int64(len(testMovies)) {
+		t.Errorf("movies count = %d, want %d", info.GetMoviesCount(), len(testMovies))
+	}
+	if info.GetPublishedAt() != testPublishedAt.Unix() {
+		t.Errorf("published at = %d, want %d", info.GetPublishedAt(), testPublishedAt.Unix())
+	}
+}
+
+func TestGRPCNoCurrentCatalog(t *testing.T) {
+	client := newTestClient(t, "empty")
+
+	_, err := client.GetCatalogInfo(context.Background(), &pb.GetCatalogInfoRequest{})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("err = %v, want code %s", err, codes.NotFound)
+	}
+}
+
+func TestGRPCListChannels(t *testing.T) {
+	client := newTestClient(t, "")
+
+	resp, err := client.ListChannels(context.Background(), &pb.ListChannelsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	channels := resp.GetChannels()
+	if len(channels) != 1 || channels[0].GetId() != 1 || channels[0].GetName() != "ARD" {
+		t.Errorf("channels = %v, want [1 ARD]", channels)
+	}
+}
+
+func TestGRPCSearchMovies(t *testing.T) {
+	client := newTestClient(t, "")
+
+	stream, err := client.SearchMovies(context.Background(),
+		&pb.SearchMoviesRequest{Query: "tatort"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles, stableIDs []string
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles = append(titles, entry.GetTitle())
+		stableIDs = append(stableIDs, entry.GetStableId())
+	}
+
+	if got := strings.Join(titles, ","); got != "Das Team,Der Fall" {
+		t.Errorf("titles = %s, want Das Team,Der Fall", got)
+	}
+	if len(stableIDs) == 2 && (stableIDs[0] != testMovies[0][1] || stableIDs[1] != testMovies[1][1]) {
+		t.Errorf("stable IDs = %v, want those of the movies", stableIDs)
+	}
+}
+
+func TestGRPCStreamCatalog(t *testing.T) {
+	client := newTestClient(t, "")
+
+	stream, err := client.StreamCatalog(context.Background(),
+		&pb.StreamCatalogRequest{ChunkSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chunks []*pb.MovieCatalogChunk
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if len(chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(chunks))
+	}
+	if chunks[0].GetInfo() == nil || len(chunks[0].GetTopics()) != 2 ||
+		len(chunks[0].GetMovies()) != 0 {
+		t.Errorf("first chunk = %v, want info, channels and topics only", chunks[0])
+	}
+	if len(chunks[1].GetMovies()) != 2 || len(chunks[2].GetMovies()) != 1 {
+		t.Errorf("movie chunks have %d and %d movies, want 2 and 1",
+			len(chunks[1].GetMovies()), len(chunks[2].GetMovies()))
+	}
+}
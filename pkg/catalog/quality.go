@@ -0,0 +1,31 @@
+package catalog
+
+// Stream qualities of a movie
+const (
+	QualityHD    = "hd"
+	QualitySD    = "sd"
+	QualitySmall = "small"
+)
+
+// ValidQuality checks if the quality is supported. An empty quality is valid
+// and selects SD.
+func ValidQuality(quality string) bool {
+	switch quality {
+	case "", QualityHD, QualitySD, QualitySmall:
+		return true
+	}
+	return false
+}
+
+// StreamURL returns the URL of the stream in the given quality. If the movie
+// doesn't provide the quality, the SD stream is returned instead. The second
+// result is the quality actually selected.
+func (m Movie) StreamURL(quality string) (string, string) {
+	switch {
+	case quality == QualityHD && m.HDFormatURL != "":
+		return m.HDFormatURL, QualityHD
+	case quality == QualitySmall && m.SmallFormatURL != "":
+		return m.SmallFormatURL, QualitySmall
+	}
+	return m.URL, QualitySD
+}
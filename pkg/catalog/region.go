@@ -0,0 +1,36 @@
+package catalog
+
+import "strings"
+
+// NormalizeRegion converts a region given by a client into an ISO 3166-1
+// country code. Ok is false if the region isn't a two letter code.
+func NormalizeRegion(region string) (string, bool) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if len(region) != 2 {
+		return "", false
+	}
+	for _, r := range region {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+
+	return region, true
+}
+
+// AvailableIn checks if the movie can be played in the given country. Movies
+// without geo restriction are available everywhere, as are all movies if the
+// region is unknown.
+func (m Movie) AvailableIn(region string) bool {
+	if region == "" || len(m.GeoCodes) == 0 {
+		return true
+	}
+
+	for _, code := range m.GeoCodes {
+		if code == region {
+			return true
+		}
+	}
+
+	return false
+}
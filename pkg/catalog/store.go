@@ -0,0 +1,555 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrNoCurrentCatalog is returned if the registry doesn't contain a catalog
+// marked as current, e.g. because nothing has been imported yet.
+var ErrNoCurrentCatalog = errors.New("no current catalog")
+
+// ErrCatalogNotFound is returned if the requested catalog isn't registered.
+var ErrCatalogNotFound = errors.New("catalog not found")
+
+// ErrEntryNotFound is returned if the requested channel or topic doesn't
+// exist in the catalog.
+var ErrEntryNotFound = errors.New("entry not found")
+
+// ErrMovieNotFound is returned if the requested movie doesn't exist in the
+// catalog.
+var ErrMovieNotFound = errors.New("movie not found")
+
+// Catalog describes an imported movie list as recorded in the registry. The
+// hash is also the name of the database schema holding the list.
+type Catalog struct {
+	Hash          string    `json:"hash"`
+	Version       int       `json:"version"`
+	PublishedAt   time.Time `json:"publishedAt"`
+	ImportedAt    time.Time `json:"importedAt"`
+	ChannelsCount int       `json:"channelsCount"`
+	TopicsCount   int       `json:"topicsCount"`
+	MoviesCount   int       `json:"moviesCount"`
+	IsCurrent     bool      `json:"isCurrent"`
+}
+
+// Movie is a single movie entry of a catalog. The ID is only valid within the
+// catalog, the stable ID identifies the movie across catalogs.
+type Movie struct {
+	ID             int64
+	StableID       string
+	GroupID        string
+	ChannelID      int64
+	TopicID        int64
+	Topic          string
+	Title          string
+	PublishedAt    time.Time
+	Duration       int64
+	Size           int64
+	Descr          string
+	URL            string
+	WebsiteURL     string
+	SubTitleURL    string
+	SmallFormatURL string
+	HDFormatURL    string
+	UnixDate       int64
+	HistoryURL     string
+	Geo            string
+	GeoCodes       []string
+	IsNew          bool
+	Series         string
+	Season         int
+	Episode        int
+	Part           int
+
+	// Accessibility and language variants link to their base broadcast by
+	// its stable ID.
+	AudioDescription bool
+	SignLanguage     bool
+	OriginalVersion  bool
+	BaseID           string
+}
+
+// Sort orders supported by the store
+const (
+	SortByID              = "id"
+	SortByPublishedAt     = "publishedAt"
+	SortByPublishedAtDesc = "-publishedAt"
+	SortByDuration        = "duration"
+	SortByDurationDesc    = "-duration"
+	SortByEpisode         = "episode"
+	SortByEpisodeDesc     = "-episode"
+)
+
+var sortClauses = map[string]string{
+	SortByID:              "id",
+	SortByPublishedAt:     "published_at, id",
+	SortByPublishedAtDesc: "published_at DESC, id",
+	SortByDuration:        "duration, id",
+	SortByDurationDesc:    "duration DESC, id",
+	SortByEpisode: `series, nullif(season, 0), nullif(episode, 0),
+        nullif(part, 0), published_at, id`,
+	SortByEpisodeDesc: `series DESC, nullif(season, 0) DESC NULLS LAST,
+        nullif(episode, 0) DESC NULLS LAST, nullif(part, 0) DESC NULLS LAST,
+        published_at DESC, id`,
+}
+
+// MovieFilter restricts the movies returned by the store. Zero values don't
+// restrict anything. The durations are given in seconds. The region excludes
+// movies which are geo-blocked in the given country. Collapse returns only
+// the earliest broadcast of each group of duplicates matching the filter. The
+// variant flags are only applied if set, so false excludes the variant.
+// AddedIn restricts the movies to those recorded as added by switching to the
+// catalog with the given hash.
+type MovieFilter struct {
+	ID          int64
+	StableID    string
+	GroupIDs    []string
+	Query       string
+	ChannelID   int64
+	TopicID     int64
+	MinDuration int64
+	MaxDuration int64
+	Series      string
+	Season      int64
+	Episode     int64
+	Region      string
+	Collapse    bool
+
+	AudioDescription *bool
+	SignLanguage     *bool
+	OriginalVersion  *bool
+	BaseID           string
+	AddedIn          string
+
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// ValidSort checks if the given sort order is supported by the store. An
+// empty sort order is valid and sorts by ID.
+func ValidSort(sort string) bool {
+	_, ok := sortClauses[sort]
+	return sort == "" || ok
+}
+
+// Store provides read access to the imported catalogs. It's shared by the REST
+// and the gRPC API.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new store on top of the given database.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Ping checks if the database is reachable.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// FindCurrentCatalog returns the catalog which is marked as current in the
+// registry.
+func (s *Store) FindCurrentCatalog(ctx context.Context) (Catalog, error) {
+	var result Catalog
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT hash, version, published_at, imported_at, channels_count,
+            topics_count, movies_count, is_current
+        FROM catalogs WHERE is_current`).Scan(&result.Hash, &result.Version,
+		&result.PublishedAt, &result.ImportedAt, &result.ChannelsCount,
+		&result.TopicsCount, &result.MoviesCount, &result.IsCurrent)
+	if err == sql.ErrNoRows {
+		return result, ErrNoCurrentCatalog
+	}
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// FindAllCatalogs returns all registered catalogs, the most recently published
+// first.
+func (s *Store) FindAllCatalogs(ctx context.Context) ([]Catalog, error) {
+	var result []Catalog
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT hash, version, published_at, imported_at, channels_count,
+            topics_count, movies_count, is_current
+        FROM catalogs ORDER BY published_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cat Catalog
+		if err := rows.Scan(&cat.Hash, &cat.Version, &cat.PublishedAt,
+			&cat.ImportedAt, &cat.ChannelsCount, &cat.TopicsCount,
+			&cat.MoviesCount, &cat.IsCurrent); err != nil {
+			return nil, err
+		}
+
+		result = append(result, cat)
+	}
+
+	return result, rows.Err()
+}
+
+// FindCatalog returns the registered catalog with the given hash.
+func (s *Store) FindCatalog(ctx context.Context, hash string) (Catalog, error) {
+	var result Catalog
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT hash, version, published_at, imported_at, channels_count,
+            topics_count, movies_count, is_current
+        FROM catalogs WHERE hash = $1`, hash).Scan(&result.Hash,
+		&result.Version, &result.PublishedAt, &result.ImportedAt,
+		&result.ChannelsCount, &result.TopicsCount, &result.MoviesCount,
+		&result.IsCurrent)
+	if err == sql.ErrNoRows {
+		return result, ErrCatalogNotFound
+	}
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// CountMovies returns the number of movies matching the filter. Limit and
+// offset of the filter are ignored.
+func (s *Store) CountMovies(ctx context.Context, schema string, filter MovieFilter) (int, error) {
+	result := 0
+
+	from, args := movieSource(schema, filter)
+	sqlStmt := fmt.Sprintf("SELECT COUNT(id) FROM %s", from)
+	err := s.db.QueryRowContext(ctx, sqlStmt, args...).Scan(&result)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// FindMovies returns all movies matching the filter.
+func (s *Store) FindMovies(ctx context.Context, schema string, filter MovieFilter) ([]Movie, error) {
+	var result []Movie
+
+	err := s.EachMovie(ctx, schema, filter, func(movie Movie) error {
+		result = append(result, movie)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindMovie returns the movie with the given ID. Besides the numeric ID of the
+// catalog, the stable ID is accepted.
+func (s *Store) FindMovie(ctx context.Context, schema string, id string) (Movie, error) {
+	var filter MovieFilter
+
+	if numericID, err := strconv.ParseInt(id, 10, 64); err == nil && numericID > 0 {
+		filter.ID = numericID
+	} else if len(id) == 32 {
+		filter.StableID = strings.ToLower(id)
+	} else {
+		return Movie{}, ErrMovieNotFound
+	}
+	filter.Limit = 1
+
+	movies, err := s.FindMovies(ctx, schema, filter)
+	if err != nil {
+		return Movie{}, err
+	}
+	if len(movies) == 0 {
+		return Movie{}, ErrMovieNotFound
+	}
+
+	return movies[0], nil
+}
+
+// FindGroupAlternatives returns the movies of the given groups except the
+// given representatives, mapped by group ID. The alternatives are ordered by
+// broadcast time.
+func (s *Store) FindGroupAlternatives(ctx context.Context, schema string, representatives []Movie) (map[string][]Movie, error) {
+	result := make(map[string][]Movie)
+
+	var groupIDs []string
+	ids := make(map[int64]bool)
+	for _, movie := range representatives {
+		if movie.GroupID != "" {
+			groupIDs = append(groupIDs, movie.GroupID)
+		}
+		ids[movie.ID] = true
+	}
+	if len(groupIDs) == 0 {
+		return result, nil
+	}
+
+	filter := MovieFilter{GroupIDs: groupIDs, Sort: SortByPublishedAt}
+	err := s.EachMovie(ctx, schema, filter, func(movie Movie) error {
+		if !ids[movie.ID] {
+			result[movie.GroupID] = append(result[movie.GroupID], movie)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// EachMovie calls fn for every movie matching the filter. Unlike FindMovies the
+// movies are not collected in memory, which makes it suitable for streaming
+// whole catalogs. If fn returns an error the iteration stops and the error is
+// returned.
+func (s *Store) EachMovie(ctx context.Context, schema string, filter MovieFilter, fn func(Movie) error) error {
+	orderBy, ok := sortClauses[filter.Sort]
+	if !ok {
+		orderBy = sortClauses[SortByID]
+	}
+
+	from, args := movieSource(schema, filter)
+	sqlStmt := fmt.Sprintf(
+		`SELECT id, stable_id, group_id, channel_id, topic_id, topic, title,
+            published_at, duration, size, descr, url, website_url,
+            sub_title_url, small_format_url, hd_format_url, unix_date,
+            history_url, geo, geo_codes, is_new, series, season, episode,
+            part, audio_description, sign_language, original_version, base_id
+        FROM %s ORDER BY %s`, from, orderBy)
+
+	if filter.Limit > 0 {
+		sqlStmt = fmt.Sprintf("%s LIMIT %d", sqlStmt, filter.Limit)
+	}
+
+	if filter.Offset > 0 {
+		sqlStmt = fmt.Sprintf("%s OFFSET %d", sqlStmt, filter.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlStmt, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movie Movie
+		var baseID sql.NullString
+		if err := rows.Scan(&movie.ID, &movie.StableID, &movie.GroupID,
+			&movie.ChannelID, &movie.TopicID, &movie.Topic, &movie.Title,
+			&movie.PublishedAt, &movie.Duration, &movie.Size, &movie.Descr,
+			&movie.URL, &movie.WebsiteURL, &movie.SubTitleURL, &movie.SmallFormatURL,
+			&movie.HDFormatURL, &movie.UnixDate, &movie.HistoryURL, &movie.Geo,
+			pq.Array(&movie.GeoCodes), &movie.IsNew, &movie.Series,
+			&movie.Season, &movie.Episode, &movie.Part,
+			&movie.AudioDescription, &movie.SignLanguage,
+			&movie.OriginalVersion, &baseID); err != nil {
+			return err
+		}
+		movie.BaseID = baseID.String
+
+		if err := fn(movie); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// FindAllChannels returns all channels of the catalog mapped by their ID.
+func (s *Store) FindAllChannels(ctx context.Context, schema string) (map[int64]string, error) {
+	return s.findAllMappedEntries(ctx, schema, "channels")
+}
+
+// FindAllTopics returns all topics of the catalog mapped by their ID.
+func (s *Store) FindAllTopics(ctx context.Context, schema string) (map[int64]string, error) {
+	return s.findAllMappedEntries(ctx, schema, "topics")
+}
+
+// FindChannel returns the name of the channel with the given ID.
+func (s *Store) FindChannel(ctx context.Context, schema string, id int64) (string, error) {
+	return s.findMappedEntry(ctx, schema, "channels", id)
+}
+
+// FindTopic returns the name of the topic with the given ID.
+func (s *Store) FindTopic(ctx context.Context, schema string, id int64) (string, error) {
+	return s.findMappedEntry(ctx, schema, "topics", id)
+}
+
+func (s *Store) findMappedEntry(ctx context.Context, schema, tableName string, id int64) (string, error) {
+	var result string
+
+	sqlStmt := fmt.Sprintf("SELECT name FROM %s.%s WHERE id = $1",
+		pq.QuoteIdentifier(schema), tableName)
+	err := s.db.QueryRowContext(ctx, sqlStmt, id).Scan(&result)
+	if err == sql.ErrNoRows {
+		return "", ErrEntryNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func (s *Store) findAllMappedEntries(ctx context.Context, schema, tableName string) (map[int64]string, error) {
+	var result map[int64]string
+
+	result = make(map[int64]string)
+	sqlStmt := fmt.Sprintf("SELECT id, name FROM %s.%s",
+		pq.QuoteIdentifier(schema), tableName)
+
+	rows, err := s.db.QueryContext(ctx, sqlStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+
+		result[id] = name
+	}
+
+	return result, rows.Err()
+}
+
+// movieSource builds the FROM clause selecting the movies matching the filter.
+// Collapsed movies are ranked within their group by a subquery, so filters
+// apply before the representative of a group is chosen.
+func movieSource(schema string, filter MovieFilter) (string, []interface{}) {
+	where, args := movieFilterClause(filter)
+	table := pq.QuoteIdentifier(schema) + ".movies"
+
+	if !filter.Collapse {
+		return table + where, args
+	}
+
+	return fmt.Sprintf(`(SELECT *, row_number() OVER (
+            PARTITION BY coalesce(group_id, stable_id)
+            ORDER BY published_at, id) AS group_rank
+        FROM %s%s) AS movies WHERE group_rank = 1`, table, where), args
+}
+
+// movieFilterClause builds the SQL where clause and its arguments for the
+// given filter. If the filter doesn't restrict anything, an empty clause is
+// returned.
+func movieFilterClause(filter MovieFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if filter.ID > 0 {
+		args = append(args, filter.ID)
+		conds = append(conds, fmt.Sprintf("id = $%d", len(args)))
+	}
+
+	if filter.StableID != "" {
+		args = append(args, filter.StableID)
+		conds = append(conds, fmt.Sprintf("stable_id = $%d", len(args)))
+	}
+
+	if len(filter.GroupIDs) > 0 {
+		args = append(args, pq.StringArray(filter.GroupIDs))
+		conds = append(conds, fmt.Sprintf("group_id = ANY($%d)", len(args)))
+	}
+
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		conds = append(conds, fmt.Sprintf("(title ILIKE $%d OR topic ILIKE $%d)",
+			len(args), len(args)))
+	}
+
+	if filter.ChannelID > 0 {
+		args = append(args, filter.ChannelID)
+		conds = append(conds, fmt.Sprintf("channel_id = $%d", len(args)))
+	}
+
+	if filter.TopicID > 0 {
+		args = append(args, filter.TopicID)
+		conds = append(conds, fmt.Sprintf("topic_id = $%d", len(args)))
+	}
+
+	if filter.MinDuration > 0 {
+		args = append(args, filter.MinDuration)
+		conds = append(conds, fmt.Sprintf("duration >= $%d", len(args)))
+	}
+
+	if filter.MaxDuration > 0 {
+		args = append(args, filter.MaxDuration)
+		conds = append(conds, fmt.Sprintf("duration <= $%d", len(args)))
+	}
+
+	if filter.Series != "" {
+		args = append(args, filter.Series)
+		conds = append(conds, fmt.Sprintf("series = $%d", len(args)))
+	}
+
+	if filter.Season > 0 {
+		args = append(args, filter.Season)
+		conds = append(conds, fmt.Sprintf("season = $%d", len(args)))
+	}
+
+	if filter.Episode > 0 {
+		args = append(args, filter.Episode)
+		conds = append(conds, fmt.Sprintf("episode = $%d", len(args)))
+	}
+
+	flags := []struct {
+		column string
+		val    *bool
+	}{
+		{"audio_description", filter.AudioDescription},
+		{"sign_language", filter.SignLanguage},
+		{"original_version", filter.OriginalVersion},
+	}
+	for _, f := range flags {
+		if f.val != nil {
+			args = append(args, *f.val)
+			conds = append(conds, fmt.Sprintf("%s = $%d", f.column, len(args)))
+		}
+	}
+
+	if filter.BaseID != "" {
+		args = append(args, filter.BaseID)
+		conds = append(conds, fmt.Sprintf("base_id = $%d", len(args)))
+	}
+
+	if filter.AddedIn != "" {
+		args = append(args, filter.AddedIn)
+		conds = append(conds, fmt.Sprintf(
+			`stable_id IN (SELECT stable_id FROM catalog_changes
+            WHERE catalog_hash = $%d AND kind = '%s')`, len(args), ChangeAdded))
+	}
+
+	if filter.Region != "" {
+		args = append(args, filter.Region)
+		conds = append(conds, fmt.Sprintf(
+			"(geo_codes IS NULL OR cardinality(geo_codes) = 0 OR $%d = ANY(geo_codes))",
+			len(args)))
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
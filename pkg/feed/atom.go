@@ -0,0 +1,47 @@
+package feed
+
+import "encoding/xml"
+
+// AtomFeed is the root element of an Atom feed, see RFC 4287.
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   *AtomPerson `xml:"author"`
+	Links    []AtomLink  `xml:"link"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+// AtomEntry is a single entry of the feed.
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []AtomCategory `xml:"category"`
+	Links      []AtomLink     `xml:"link"`
+}
+
+// AtomLink links a feed or entry to a resource. Enclosures use the relation
+// "enclosure" and give the length in bytes.
+type AtomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Title  string `xml:"title,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+// AtomCategory categorizes an entry.
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// AtomPerson describes the author of a feed.
+type AtomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
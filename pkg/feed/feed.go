@@ -0,0 +1,40 @@
+// Package feed implements the XML documents of RSS 2.0 and Atom feeds.
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Content types of the feeds
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// Write writes the feed document including the XML declaration.
+func Write(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// RSSDate formats the time as RFC 822 date used by RSS.
+func RSSDate(t time.Time) string {
+	return t.Format(time.RFC1123Z)
+}
+
+// AtomDate formats the time as RFC 3339 date used by Atom.
+func AtomDate(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
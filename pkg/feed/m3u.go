@@ -0,0 +1,71 @@
+package feed
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// M3UContentType is the content type of UTF-8 encoded M3U playlists.
+const M3UContentType = "audio/x-mpegurl; charset=utf-8"
+
+// Playlist is an extended M3U playlist as understood by VLC, Kodi and IPTV
+// players.
+type Playlist struct {
+	Title   string
+	Entries []PlaylistEntry
+}
+
+// PlaylistEntry is a single stream of the playlist. The duration is given in
+// seconds, -1 if unknown.
+type PlaylistEntry struct {
+	Duration int64
+	Title    string
+	URL      string
+}
+
+// WriteTo writes the playlist in the extended M3U format.
+func (p *Playlist) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+
+	fmt.Fprintln(cw, "#EXTM3U")
+	if p.Title != "" {
+		fmt.Fprintf(cw, "#PLAYLIST:%s\n", playlistText(p.Title))
+	}
+
+	for _, e := range p.Entries {
+		fmt.Fprintf(cw, "#EXTINF:%d,%s\n", e.Duration, playlistText(e.Title))
+		fmt.Fprintln(cw, playlistText(e.URL))
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, bw.Flush()
+}
+
+// lineBreaks replaces line breaks, which would break the line based format.
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+func playlistText(s string) string {
+	return strings.TrimSpace(lineBreaks.Replace(s))
+}
+
+// countWriter counts the bytes written and keeps the first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
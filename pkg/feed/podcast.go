@@ -0,0 +1,69 @@
+package feed
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+)
+
+// Namespaces of the podcast extensions
+const (
+	ITunesNamespace  = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+	PodcastNamespace = "https://podcastindex.org/namespace/1.0"
+)
+
+// podcastGUIDNamespace is the UUID namespace of podcast GUIDs as defined by
+// the Podcasting 2.0 namespace.
+var podcastGUIDNamespace = [16]byte{
+	0xea, 0xd4, 0xc2, 0x36, 0xbf, 0x58, 0x58, 0xc6,
+	0xa2, 0xc6, 0xa6, 0xb2, 0x8d, 0x12, 0x8c, 0xb6,
+}
+
+// ITunesCategory categorizes a podcast.
+type ITunesCategory struct {
+	Text string `xml:"text,attr"`
+}
+
+// PodcastAlternateEnclosure offers the media file of an item in another
+// quality or format.
+type PodcastAlternateEnclosure struct {
+	Type    string          `xml:"type,attr"`
+	Length  int64           `xml:"length,attr,omitempty"`
+	Title   string          `xml:"title,attr,omitempty"`
+	Default bool            `xml:"default,attr,omitempty"`
+	Sources []PodcastSource `xml:"podcast:source"`
+}
+
+// PodcastSource is a location of an alternate enclosure.
+type PodcastSource struct {
+	URI string `xml:"uri,attr"`
+}
+
+// EnablePodcast declares the namespaces of the podcast extensions.
+func (rss *RSS) EnablePodcast() {
+	rss.ITunesNS = ITunesNamespace
+	rss.PodcastNS = PodcastNamespace
+}
+
+// PodcastGUID derives the podcast GUID from the feed URL, which is an UUIDv5
+// of the URL without scheme and trailing slashes.
+func PodcastGUID(feedURL string) string {
+	if i := strings.Index(feedURL, "://"); i >= 0 {
+		feedURL = feedURL[i+3:]
+	}
+	feedURL = strings.TrimRight(feedURL, "/")
+
+	h := sha1.New()
+	h.Write(podcastGUIDNamespace[:])
+	h.Write([]byte(feedURL))
+	u := h.Sum(nil)[:16]
+	u[6] = (u[6] & 0x0f) | 0x50
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// ITunesDuration formats a duration given in seconds.
+func ITunesDuration(seconds int64) string {
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
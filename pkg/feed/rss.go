@@ -0,0 +1,67 @@
+package feed
+
+import "encoding/xml"
+
+// RSS is the root element of a RSS 2.0 feed. The namespaces of the podcast
+// extensions are only declared for podcasts, see EnablePodcast.
+type RSS struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ITunesNS  string     `xml:"xmlns:itunes,attr,omitempty"`
+	PodcastNS string     `xml:"xmlns:podcast,attr,omitempty"`
+	Channel   RSSChannel `xml:"channel"`
+}
+
+// NewRSS creates a RSS 2.0 feed with the given channel.
+func NewRSS(channel RSSChannel) *RSS {
+	return &RSS{Version: "2.0", Channel: channel}
+}
+
+// RSSChannel describes the feed and contains its items. The iTunes and
+// podcast fields are only set for podcasts.
+type RSSChannel struct {
+	Title          string          `xml:"title"`
+	Link           string          `xml:"link"`
+	Description    string          `xml:"description"`
+	Language       string          `xml:"language,omitempty"`
+	LastBuildDate  string          `xml:"lastBuildDate,omitempty"`
+	TTL            int             `xml:"ttl,omitempty"`
+	ITunesAuthor   string          `xml:"itunes:author,omitempty"`
+	ITunesSummary  string          `xml:"itunes:summary,omitempty"`
+	ITunesType     string          `xml:"itunes:type,omitempty"`
+	ITunesExplicit string          `xml:"itunes:explicit,omitempty"`
+	ITunesCategory *ITunesCategory `xml:"itunes:category"`
+	PodcastGUID    string          `xml:"podcast:guid,omitempty"`
+	PodcastMedium  string          `xml:"podcast:medium,omitempty"`
+	Items          []RSSItem       `xml:"item"`
+}
+
+// RSSItem is a single entry of the feed.
+type RSSItem struct {
+	Title               string                      `xml:"title"`
+	Link                string                      `xml:"link,omitempty"`
+	Description         string                      `xml:"description,omitempty"`
+	Category            string                      `xml:"category,omitempty"`
+	GUID                RSSGUID                     `xml:"guid"`
+	PubDate             string                      `xml:"pubDate,omitempty"`
+	Enclosure           *RSSEnclosure               `xml:"enclosure"`
+	ITunesDuration      string                      `xml:"itunes:duration,omitempty"`
+	ITunesSummary       string                      `xml:"itunes:summary,omitempty"`
+	ITunesEpisodeType   string                      `xml:"itunes:episodeType,omitempty"`
+	ITunesExplicit      string                      `xml:"itunes:explicit,omitempty"`
+	AlternateEnclosures []PodcastAlternateEnclosure `xml:"podcast:alternateEnclosure"`
+}
+
+// RSSGUID identifies an item. Feed readers use it to detect known items.
+type RSSGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSSEnclosure attaches a media file to an item. The length is given in
+// bytes.
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
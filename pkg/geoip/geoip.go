@@ -0,0 +1,60 @@
+// Package geoip resolves the country of a client IP address using a MaxMind
+// DB file, e.g. GeoLite2-Country or DB-IP Country Lite.
+package geoip
+
+import (
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// DB looks up the country of IP addresses. It's safe for concurrent use.
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+// record holds the fields of a country or city database which are needed.
+// The registered country is used as fallback, e.g. for anycast networks.
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// Open opens the database file at the given path.
+func Open(path string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{reader: reader}, nil
+}
+
+// Country returns the ISO 3166-1 code of the country the IP address is
+// located in. An empty string is returned if the address is invalid or
+// unknown, e.g. for private networks.
+func (db *DB) Country(ip string) string {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+
+	var rec record
+	if err := db.reader.Lookup(addr, &rec); err != nil {
+		return ""
+	}
+	if rec.Country.ISOCode != "" {
+		return rec.Country.ISOCode
+	}
+
+	return rec.RegisteredCountry.ISOCode
+}
+
+// Close releases the database file.
+func (db *DB) Close() error {
+	return db.reader.Close()
+}
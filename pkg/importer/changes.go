@@ -0,0 +1,133 @@
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// changeLogRetention is how long entries of the change log are kept.
+const changeLogRetention = 30 * 24 * time.Hour
+
+// Kinds of changes recorded in the change log
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+)
+
+// fingerprintColumns are the columns compared to detect changed metadata of
+// movies with equal stable ID. The title is part of the stable ID, so an
+// edited title results in a removed and an added movie instead.
+var fingerprintColumns = []string{"descr", "duration", "size", "url",
+	"website_url", "sub_title_url", "small_format_url", "hd_format_url", "geo"}
+
+// changeColumns are the movie details copied into the change log.
+var changeColumns = []string{"channel", "topic", "title", "published_at",
+	"duration", "url"}
+
+// createChangeLog creates the change log if it doesn't exist. Each entry
+// records a movie added, removed or changed by switching the current catalog.
+// The movie details are copied, since the schema of the previous catalog is
+// dropped eventually.
+func createChangeLog(txn *sql.Tx) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS catalog_changes (
+			id bigserial NOT NULL PRIMARY KEY,
+			catalog_hash varchar(32) NOT NULL,
+			previous_hash varchar(32) NOT NULL,
+			changed_at timestamptz NOT NULL,
+			kind varchar(8) NOT NULL,
+			stable_id char(32) NOT NULL,
+			channel text,
+			topic text,
+			title text,
+			published_at timestamptz,
+			duration integer,
+			url varchar(2047)
+		)`,
+		"CREATE INDEX IF NOT EXISTS catalog_changes_changed_at_idx ON catalog_changes (changed_at)",
+		"CREATE INDEX IF NOT EXISTS catalog_changes_catalog_hash_idx ON catalog_changes (catalog_hash)",
+	}
+
+	for _, stmt := range stmts {
+		if _, err := txn.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordChanges compares the movies of the previous and the new current
+// catalog by their stable IDs and appends the differences to the change log.
+// Entries older than the retention are removed. Catalogs imported before
+// stable IDs were introduced can't be compared and are skipped.
+func recordChanges(txn *sql.Tx, previous, current string, now time.Time) error {
+	err := createChangeLog(txn)
+	if err != nil {
+		return err
+	}
+
+	_, err = txn.Exec("DELETE FROM catalog_changes WHERE changed_at < $1",
+		now.Add(-changeLogRetention))
+	if err != nil {
+		return err
+	}
+
+	var comparable bool
+	err = txn.QueryRow(`SELECT EXISTS(SELECT 1 FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = 'movies'
+			AND column_name = 'stable_id')`, previous).Scan(&comparable)
+	if err != nil || !comparable {
+		return err
+	}
+
+	prev := pq.QuoteIdentifier(previous) + ".movies"
+	cur := pq.QuoteIdentifier(current) + ".movies"
+
+	stmts := []struct {
+		kind  string
+		query string
+	}{
+		{changeAdded, fmt.Sprintf(`SELECT DISTINCT ON (n.stable_id) n.stable_id, %s
+			FROM %s n WHERE NOT EXISTS (
+				SELECT 1 FROM %s o WHERE o.stable_id = n.stable_id)
+			ORDER BY n.stable_id, n.id`, qualify("n", changeColumns), cur, prev)},
+		{changeRemoved, fmt.Sprintf(`SELECT DISTINCT ON (o.stable_id) o.stable_id, %s
+			FROM %s o WHERE NOT EXISTS (
+				SELECT 1 FROM %s n WHERE n.stable_id = o.stable_id)
+			ORDER BY o.stable_id, o.id`, qualify("o", changeColumns), prev, cur)},
+		{changeChanged, fmt.Sprintf(`SELECT DISTINCT ON (n.stable_id) n.stable_id, %s
+			FROM %s n JOIN %s o ON o.stable_id = n.stable_id
+			WHERE md5(concat_ws(chr(0), %s)) <> md5(concat_ws(chr(0), %s))
+			ORDER BY n.stable_id, n.id`, qualify("n", changeColumns), cur, prev,
+			qualify("n", fingerprintColumns), qualify("o", fingerprintColumns))},
+	}
+
+	for _, s := range stmts {
+		_, err = txn.Exec(fmt.Sprintf(`INSERT INTO catalog_changes (
+				catalog_hash, previous_hash, changed_at, kind, stable_id, %s)
+			SELECT $1::varchar, $2::varchar, $3::timestamptz, $4::varchar, c.*
+			FROM (%s) c`,
+			strings.Join(changeColumns, ", "), s.query),
+			current, previous, now, s.kind)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// qualify joins the columns qualified by the table alias.
+func qualify(alias string, columns []string) string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = alias + "." + column
+	}
+	return strings.Join(result, ", ")
+}
@@ -0,0 +1,245 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// advisoryLockKey is the key of the PostgreSQL advisory lock, which ensures
+// that only a single daemon imports at a time.
+const advisoryLockKey = 0x6d6474686b // "mdthk"
+
+// metaDataPrefixSize is the number of bytes read from a movie list to extract
+// its meta data. The meta data and the column names are located at the very
+// beginning of the list.
+const metaDataPrefixSize = 64 << 10
+
+// ErrLocked is returned if another process holds the import lock.
+var ErrLocked = errors.New("import locked by another process")
+
+// DefaultMirrors are the MediathekView mirrors polled by the daemon.
+var DefaultMirrors = []string{
+	DefaultMovieListURL,
+	"https://verteiler1.mediathekview.de/Filmliste-akt.xz",
+	"https://verteiler2.mediathekview.de/Filmliste-akt.xz",
+}
+
+// Status describes the last run of the daemon.
+type Status struct {
+	LastRunAt     time.Time `json:"lastRunAt,omitempty"`
+	LastSuccessAt time.Time `json:"lastSuccessAt,omitempty"`
+	LastImportAt  time.Time `json:"lastImportAt,omitempty"`
+	LastError     string    `json:"lastError,omitempty"`
+	Mirror        string    `json:"mirror,omitempty"`
+	Hash          string    `json:"hash,omitempty"`
+	Imported      bool      `json:"imported"`
+	Activated     bool      `json:"activated"`
+	Dropped       []string  `json:"dropped,omitempty"`
+	NextRunAt     time.Time `json:"nextRunAt,omitempty"`
+}
+
+// Daemon polls the mirrors for new movie lists. If the list changed compared
+// to the registry, it's imported, made current and old catalogs are dropped.
+type Daemon struct {
+	db       *sql.DB
+	mirrors  []string
+	interval time.Duration
+
+	mu         sync.Mutex
+	status     Status
+	onActivate func(ctx context.Context, hash string)
+}
+
+// NewDaemon creates a new daemon polling the given mirrors in the given
+// interval. The mirrors are tried in order until one succeeds.
+func NewDaemon(db *sql.DB, mirrors []string, interval time.Duration) *Daemon {
+	return &Daemon{
+		db:       db,
+		mirrors:  mirrors,
+		interval: interval,
+	}
+}
+
+// OnActivate registers a function called after a catalog became current, e.g.
+// to notify webhooks. It's called outside of the import
+// lock and must be registered before the daemon runs.
+func (d *Daemon) OnActivate(fn func(ctx context.Context, hash string)) {
+	d.onActivate = fn
+}
+
+// Status returns the status of the last run.
+func (d *Daemon) Status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.status
+}
+
+// Run polls the mirrors until the context is canceled. The first run starts
+// immediately. Errors are recorded in the status and don't stop the daemon.
+func (d *Daemon) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.RunOnce(ctx); err != nil {
+			log.Println("importer: run failed:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce checks the mirrors for a new movie list and imports it if needed.
+// The status is updated accordingly.
+func (d *Daemon) RunOnce(ctx context.Context) error {
+	status := Status{LastRunAt: time.Now()}
+
+	err := d.runLocked(ctx, &status)
+	if status.Activated && d.onActivate != nil {
+		d.onActivate(ctx, status.Hash)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status.LastSuccessAt = d.status.LastSuccessAt
+	if !status.Imported {
+		status.LastImportAt = d.status.LastImportAt
+	}
+	if err != nil {
+		status.LastError = err.Error()
+	} else {
+		status.LastSuccessAt = status.LastRunAt
+	}
+	status.NextRunAt = status.LastRunAt.Add(d.interval)
+	d.status = status
+
+	return err
+}
+
+// runLocked runs the import while holding the advisory lock. Session level
+// advisory locks are bound to a connection, so a dedicated connection is used
+// for the lock.
+func (d *Daemon) runLocked(ctx context.Context, status *Status) error {
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)",
+		advisoryLockKey).Scan(&locked)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return ErrLocked
+	}
+	defer conn.ExecContext(context.Background(),
+		"SELECT pg_advisory_unlock($1)", advisoryLockKey)
+
+	return d.run(ctx, status)
+}
+
+func (d *Daemon) run(ctx context.Context, status *Status) error {
+	mirror, hash, err := d.findMovieListHash(ctx)
+	if err != nil {
+		return err
+	}
+	status.Mirror = mirror
+	status.Hash = hash
+
+	err = createRegistry(d.db)
+	if err != nil {
+		return err
+	}
+
+	current, err := isCurrentCatalog(d.db, hash)
+	if err != nil || current {
+		return err
+	}
+
+	// A previous run may have imported the list but failed to activate it
+	exists, err := movieListExists(d.db, hash)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		raw, err := ReadMovieList(ctx, mirror)
+		if err != nil {
+			return err
+		}
+
+		result, err := ImportMovieList(d.db, raw, false)
+		if err != nil {
+			return err
+		}
+		hash = result.Hash
+		status.Hash = hash
+		status.Imported = true
+		status.LastImportAt = time.Now()
+	}
+
+	status.Activated, err = ActivateCatalog(d.db, hash)
+	if err != nil {
+		return err
+	}
+
+	status.Dropped, err = CollectGarbage(d.db)
+	return err
+}
+
+// findMovieListHash tries the mirrors in order and returns the first one
+// which serves a valid movie list together with the hash of the list.
+func (d *Daemon) findMovieListHash(ctx context.Context) (string, string, error) {
+	var err error
+
+	for _, mirror := range d.mirrors {
+		var hash string
+		hash, err = PeekMovieListHash(ctx, mirror)
+		if err == nil {
+			return mirror, hash, nil
+		}
+	}
+
+	if err == nil {
+		err = errors.New("no mirrors configured")
+	}
+
+	return "", "", err
+}
+
+// PeekMovieListHash returns the hash of the movie list at the given source.
+// Only the beginning of the list containing the meta data is read.
+func PeekMovieListHash(ctx context.Context, src string) (string, error) {
+	rc, err := OpenMovieList(ctx, src)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	prefix := make([]byte, metaDataPrefixSize)
+	n, err := io.ReadFull(rc, prefix)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	meta, err := unmarshalMetaDataEntry(string(prefix[:n]))
+	if err != nil {
+		return "", err
+	}
+
+	return meta.md5Hash, nil
+}
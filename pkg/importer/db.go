@@ -2,19 +2,290 @@ package importer
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/lib/pq"
 )
 
+// ErrCatalogNotFound is returned if a catalog isn't registered.
+var ErrCatalogNotFound = errors.New("catalog not found")
+
+// ErrCatalogCurrent is returned if the current catalog should be imported
+// again. Its schema is served by the API and must not be dropped.
+var ErrCatalogCurrent = errors.New("catalog is current and can't be imported again")
+
+// createRegistry creates the catalog registry if it doesn't exist. Each
+// imported movie list lives in its own schema named by its md5 hash. The
+// registry keeps track of these schemas and marks the current one, which is
+// served by the API.
+func createRegistry(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS catalogs (
+		hash varchar(32) NOT NULL PRIMARY KEY,
+		version int,
+		published_at timestamptz,
+		imported_at timestamptz,
+		channels_count int,
+		topics_count int,
+		movies_count int,
+		is_current bool NOT NULL DEFAULT false
+	)`)
+	return err
+}
+
 func movieListExists(db *sql.DB, md5Hash string) (bool, error) {
-	return false, nil
+	var exists bool
+
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM catalogs WHERE hash = $1)",
+		md5Hash).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func isCurrentCatalog(db *sql.DB, md5Hash string) (bool, error) {
+	var current bool
+
+	err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM catalogs
+        WHERE hash = $1 AND is_current)`, md5Hash).Scan(&current)
+	if err != nil {
+		return false, err
+	}
+
+	return current, nil
+}
+
+// ActivateCatalog marks the catalog with the given hash as current. All other
+// catalogs lose their current flag in the same transaction, so there's always
+// exactly one current catalog. The changes compared to the previous current
+// catalog are recorded in the change log within the same transaction. The
+// switched result is false if the catalog already was current, so callers
+// only announce actual switches.
+func ActivateCatalog(db *sql.DB, hash string) (bool, error) {
+	start := time.Now()
+	err := createRegistry(db)
+	if err != nil {
+		return false, err
+	}
+
+	txn, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer txn.Rollback()
+
+	var exists bool
+	err = txn.QueryRow("SELECT EXISTS(SELECT 1 FROM catalogs WHERE hash = $1)",
+		hash).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, ErrCatalogNotFound
+	}
+
+	var previous string
+	err = txn.QueryRow("SELECT hash FROM catalogs WHERE is_current").Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if previous != "" && previous != hash {
+		err = recordChanges(txn, previous, hash, time.Now())
+		if err != nil {
+			return false, err
+		}
+	}
+
+	_, err = txn.Exec("UPDATE catalogs SET is_current = (hash = $1)", hash)
+	if err != nil {
+		return false, err
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		return false, err
+	}
+	observeStage(stageSwitch, start)
+
+	return previous != hash, nil
+}
+
+// CollectGarbage drops the schemas of all catalogs except the current one and
+// removes them from the registry. It returns the hashes of the dropped
+// catalogs.
+func CollectGarbage(db *sql.DB) ([]string, error) {
+	var result []string
+
+	err := createRegistry(db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT hash FROM catalogs WHERE NOT is_current")
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, hash := range hashes {
+		err := dropMovieList(db, hash)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, hash)
+	}
+
+	return result, nil
+}
+
+// dropMovieList drops the schema of the movie list and removes it from the
+// registry in a single transaction.
+func dropMovieList(db *sql.DB, hash string) error {
+	txn, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	_, err = txn.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE",
+		pq.QuoteIdentifier(hash)))
+	if err != nil {
+		return err
+	}
+
+	_, err = txn.Exec("DELETE FROM catalogs WHERE hash = $1 AND NOT is_current",
+		hash)
+	if err != nil {
+		return err
+	}
+
+	return txn.Commit()
 }
 
+// createAndPrepareSchema creates the schema for a movie list including all
+// tables. An existing schema with the same name is dropped before. Indices are
+// created separately after the bulk copy, see createIndices.
 func createAndPrepareSchema(db *sql.DB, schema string) error {
+	schema = pq.QuoteIdentifier(schema)
+
+	stmts := []string{
+		fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema),
+		fmt.Sprintf("CREATE SCHEMA %s", schema),
+		fmt.Sprintf(`CREATE TABLE %s.channels (
+			id bigint NOT NULL PRIMARY KEY,
+			name text
+		)`, schema),
+		fmt.Sprintf(`CREATE TABLE %s.topics (
+			id bigint NOT NULL PRIMARY KEY,
+			name text
+		)`, schema),
+		fmt.Sprintf(`CREATE TABLE %[1]s.movies (
+			id bigserial NOT NULL PRIMARY KEY,
+			stable_id char(32),
+			group_id char(32),
+			channel text,
+			channel_id bigint REFERENCES %[1]s.channels,
+			topic text,
+			topic_id bigint REFERENCES %[1]s.topics,
+			title text,
+			published_at timestamptz,
+			duration integer,
+			size bigint,
+			descr text,
+			url varchar(2047),
+			website_url varchar(2047),
+			sub_title_url varchar(2047),
+			small_format_url varchar(2047),
+			hd_format_url varchar(2047),
+			unix_date bigint,
+			history_url varchar(2047),
+			geo varchar(100),
+			geo_codes text[],
+			is_new bool,
+			series text,
+			season smallint,
+			episode integer,
+			part smallint,
+			audio_description bool,
+			sign_language bool,
+			original_version bool,
+			base_id char(32)
+		)`, schema),
+	}
+
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// createIndices creates the indices of the movies table. Creating them after
+// the bulk copy is a lot faster than updating them on each insert.
+func createIndices(db *sql.DB, schema string) error {
+	schema = pq.QuoteIdentifier(schema)
+
+	stmts := []string{
+		fmt.Sprintf("CREATE INDEX ON %s.movies (stable_id)", schema),
+		fmt.Sprintf("CREATE INDEX ON %s.movies (group_id)", schema),
+		fmt.Sprintf("CREATE INDEX ON %s.movies (channel_id)", schema),
+		fmt.Sprintf("CREATE INDEX ON %s.movies (topic_id)", schema),
+		fmt.Sprintf("CREATE INDEX ON %s.movies (published_at)", schema),
+		fmt.Sprintf("CREATE INDEX ON %s.movies (duration)", schema),
+		fmt.Sprintf("CREATE INDEX ON %s.movies (series, season, episode)", schema),
+		fmt.Sprintf("CREATE INDEX ON %s.movies USING gin (geo_codes)", schema),
+	}
+
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerMovieList adds the imported movie list to the registry. A previous
+// registration of the same list is replaced. The list doesn't become the
+// current one.
+func registerMovieList(db *sql.DB, meta metaDataEntry, channelsCount, topicsCount, moviesCount int) error {
+	version, err := strconv.Atoi(meta.version)
+	if err != nil {
+		return fmt.Errorf("invalid version in meta data")
+	}
+
+	_, err = db.Exec(`INSERT INTO catalogs (hash, version, published_at,
+			imported_at, channels_count, topics_count, movies_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (hash) DO UPDATE SET version = $2, published_at = $3,
+			imported_at = $4, channels_count = $5, topics_count = $6,
+			movies_count = $7`,
+		meta.md5Hash, version, meta.publishedAt, time.Now(), channelsCount,
+		topicsCount, moviesCount)
+
+	return err
+}
+
 func bulkCopyChannelEntries(db *sql.DB, schema string, entries map[string]int64) error {
 	return bulkCopyMappedEntries(db, schema, "channels", entries)
 }
@@ -68,20 +339,27 @@ func bulkCopyMovieEntries(db *sql.DB, schema string, entries []movieEntry) error
 	}
 
 	stmt, err := txn.Prepare(pq.CopyInSchema(schema,
-		"movies", "channel", "channel_id", "topic", "topic_id", "title",
-		"published_at", "duration", "size", "descr", "url", "website_url",
-		"sub_title_url", "small_format_url", "hd_format_url", "unix_date",
-		"history_url", "geo", "is_new"))
+		"movies", "stable_id", "group_id", "channel", "channel_id", "topic",
+		"topic_id", "title", "published_at", "duration", "size", "descr", "url",
+		"website_url", "sub_title_url", "small_format_url", "hd_format_url",
+		"unix_date", "history_url", "geo", "geo_codes", "is_new", "series",
+		"season", "episode", "part", "audio_description", "sign_language",
+		"original_version", "base_id"))
 	if err != nil {
 		return err
 	}
 
 	for _, entry := range entries {
-		_, err = stmt.Exec(entry.channel, entry.channelID, entry.topic,
-			entry.topicID, entry.title, entry.publishedAt, entry.duration,
-			entry.size, entry.descr, entry.url, entry.websiteURL,
-			entry.subTitleURL, entry.smallFormatURL, entry.hdFormatURL,
-			entry.unixDate, entry.historyURL, entry.geo, entry.isNew)
+		_, err = stmt.Exec(entry.stableID, entry.groupID, entry.channel,
+			entry.channelID, entry.topic, entry.topicID, entry.title,
+			entry.publishedAt, entry.duration, entry.size, entry.descr,
+			entry.url, entry.websiteURL, entry.subTitleURL, entry.smallFormatURL,
+			entry.hdFormatURL, entry.unixDate, entry.historyURL, entry.geo,
+			pq.StringArray(entry.geoCodes), entry.isNew, entry.episode.Series,
+			entry.episode.Season, entry.episode.Number, entry.episode.Part,
+			entry.variant.audioDescription, entry.variant.signLanguage,
+			entry.variant.originalVersion,
+			sql.NullString{String: entry.baseID, Valid: entry.baseID != ""})
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tolerances for durations of the same broadcast. The movie list rounds
+// durations and channels cut their copies slightly differently.
+const (
+	groupDurationTolerance    = 30 // seconds
+	groupDurationTolerancePct = 2
+)
+
+// groupDescrPrefix is the number of characters of the normalized description
+// compared. Channels often append their own notes to the description.
+const groupDescrPrefix = 80
+
+// maxGroupBucket limits the number of entries compared pairwise. Larger
+// buckets stem from generic titles or file names and don't identify a
+// programme.
+const maxGroupBucket = 100
+
+// qualitySuffixPattern matches the quality markers in file names of the
+// streams, e.g. "_hd", ".l" or "-1280x720", which differ between copies.
+var qualitySuffixPattern = regexp.MustCompile(`([._-](hd|sd|hq|xl|[lmsx]|\d{3,4}x\d{3,4}|\d{3,4}p|\d{3,5}k))+$`)
+
+// assignGroups detects duplicate broadcasts, e.g. the same programme shown by
+// several channels or repeats, and assigns them a common group ID. Two movies
+// are duplicates if their durations match within a tolerance and either their
+// normalized titles and descriptions or the file names of their streams are
+// equal. Movies without description match by title alone. Groups are
+// transitive. The group ID is the stable ID of the earliest broadcast of the
+// group, movies without duplicates form a group of their own.
+func assignGroups(entries []movieEntry) {
+	parent := make([]int, len(entries))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Entries sharing a key are compared pairwise. Buckets are usually small,
+	// since the keys are specific to a programme.
+	buckets := make(map[string][]int)
+	for i, entry := range entries {
+		title := normalizeForGrouping(entry.title)
+		if title == "" {
+			continue
+		}
+		// Entries without description are compared by their title alone
+		if descr := normalizeForGrouping(entry.descr); descr != "" {
+			key := "d\x00" + title + "\x00" + truncateRunes(descr, groupDescrPrefix)
+			buckets[key] = append(buckets[key], i)
+		} else {
+			buckets["t\x00"+title] = append(buckets["t\x00"+title], i)
+		}
+		if file := streamFileKey(entry.url); file != "" {
+			buckets["f\x00"+file] = append(buckets["f\x00"+file], i)
+		}
+	}
+
+	for _, bucket := range buckets {
+		if len(bucket) > maxGroupBucket {
+			continue
+		}
+		for n, i := range bucket {
+			for _, j := range bucket[n+1:] {
+				if durationsMatch(entries[i].duration, entries[j].duration) {
+					parent[find(i)] = find(j)
+				}
+			}
+		}
+	}
+
+	// Pick the earliest broadcast of each group as representative
+	members := make(map[int][]int)
+	for i := range entries {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	for _, group := range members {
+		sort.Slice(group, func(a, b int) bool {
+			ea, eb := entries[group[a]], entries[group[b]]
+			if !ea.publishedAt.Equal(eb.publishedAt) {
+				return ea.publishedAt.Before(eb.publishedAt)
+			}
+			return ea.stableID < eb.stableID
+		})
+		groupID := entries[group[0]].stableID
+		for _, i := range group {
+			entries[i].groupID = groupID
+		}
+	}
+}
+
+// durationsMatch checks if two durations in seconds belong to the same
+// broadcast. Unknown durations only match each other.
+func durationsMatch(a, b int64) bool {
+	if a == 0 || b == 0 {
+		return a == b
+	}
+
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+
+	max := a
+	if b > a {
+		max = b
+	}
+
+	return diff <= groupDurationTolerance || diff*100 <= max*groupDurationTolerancePct
+}
+
+// normalizeForGrouping lowercases the string and reduces it to letters and
+// digits separated by single spaces, so punctuation and formatting don't
+// prevent matches.
+func normalizeForGrouping(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// streamFileKey returns the file name of the stream URL without extension and
+// quality markers. Copies of a broadcast distributed by several channels
+// usually share the file on the CDN. HLS master playlists have generic names
+// and are ignored.
+func streamFileKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	name := path.Base(u.Path)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = qualitySuffixPattern.ReplaceAllString(strings.ToLower(name), "")
+	if len(name) < 8 || name == "master" || name == "index" || name == "playlist" {
+		return ""
+	}
+
+	return name
+}
+
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
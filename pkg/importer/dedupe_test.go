@@ -0,0 +1,34 @@
+package importer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssignGroups(t *testing.T) {
+	published := time.Date(2024, 3, 1, 20, 15, 0, 0, time.UTC)
+
+	entries := []movieEntry{
+		{stableID: "a", title: "Das Team", descr: "Ein Krimi.", duration: 5400,
+			url: "https://example.org/a.mp4", publishedAt: published},
+		{stableID: "b", title: "Das Team!", descr: "Ein Krimi", duration: 5410,
+			url: "https://example.org/b.mp4", publishedAt: published.Add(time.Hour)},
+		{stableID: "c", title: "Wetter", duration: 120,
+			url: "https://example.org/c.mp4", publishedAt: published},
+		{stableID: "d", title: "Wetter", duration: 125,
+			url: "https://example.org/d.mp4", publishedAt: published.Add(-time.Hour)},
+		{stableID: "e", title: "Wetter", duration: 600,
+			url: "https://example.org/e.mp4", publishedAt: published},
+		{stableID: "f", title: "Das Team", duration: 5400,
+			url: "https://example.org/f.mp4", publishedAt: published},
+	}
+	assignGroups(entries)
+
+	want := map[string]string{"a": "a", "b": "a", "c": "d", "d": "d", "e": "e", "f": "f"}
+	for _, entry := range entries {
+		if entry.groupID != want[entry.stableID] {
+			t.Errorf("group of %s = %s, want %s", entry.stableID, entry.groupID,
+				want[entry.stableID])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Episode holds the series information extracted from a movie title. Zero
+// values mean the information isn't given by the title.
+type Episode struct {
+	Series string
+	Season int
+	Number int
+	Part   int
+}
+
+// EpisodeRule extracts series information from a title. It returns the
+// information found, which may be incomplete, and the title with the matched
+// text removed, so subsequent rules don't match it again. Ok is false if the
+// rule doesn't match.
+type EpisodeRule interface {
+	Extract(title string) (info Episode, rest string, ok bool)
+}
+
+// RegexpRule is an episode rule based on a regular expression. The named
+// groups series, season, episode and part of the expression populate the
+// corresponding fields.
+type RegexpRule struct {
+	Pattern *regexp.Regexp
+}
+
+// NewRegexpRule compiles the expression into a rule. It panics if the
+// expression is invalid.
+func NewRegexpRule(expr string) RegexpRule {
+	return RegexpRule{Pattern: regexp.MustCompile(expr)}
+}
+
+// Extract implements EpisodeRule.
+func (r RegexpRule) Extract(title string) (Episode, string, bool) {
+	var info Episode
+
+	loc := r.Pattern.FindStringSubmatchIndex(title)
+	if loc == nil {
+		return info, title, false
+	}
+
+	for i, name := range r.Pattern.SubexpNames() {
+		if name == "" || loc[2*i] < 0 {
+			continue
+		}
+		val := title[loc[2*i]:loc[2*i+1]]
+		n, _ := strconv.Atoi(val)
+		switch name {
+		case "series":
+			info.Series = strings.TrimSpace(val)
+		case "season":
+			info.Season = n
+		case "episode":
+			info.Number = n
+		case "part":
+			info.Part = n
+		}
+	}
+
+	return info, title[:loc[0]] + " " + title[loc[1]:], true
+}
+
+// DefaultEpisodeRules cover the notations commonly used by the German
+// broadcasters, e.g. "(S03/E05)", "Staffel 2", "Folge 12" and "Teil 2".
+var DefaultEpisodeRules = []EpisodeRule{
+	NewRegexpRule(`(?i)\(?\bS(?P<season>\d{1,2})\s*[/|]?\s*E(?P<episode>\d{1,4})\b\)?`),
+	NewRegexpRule(`(?i)\bStaffel\s+(?P<season>\d{1,2})\b`),
+	NewRegexpRule(`(?i)\b(?:Folge|Episode|Ep\.)\s*(?P<episode>\d{1,4})\b`),
+	NewRegexpRule(`(?i)\bTeil\s+(?P<part>\d{1,2})\b`),
+	NewRegexpRule(`\((?P<part>\d{1,2})/\d{1,2}\)`),
+}
+
+// EpisodeExtractor applies a list of rules to the titles of the movie
+// entries. The rules run in order, each one only fills the fields not
+// populated by a previous rule.
+type EpisodeExtractor struct {
+	rules []EpisodeRule
+}
+
+// NewEpisodeExtractor creates an extractor using the given rules.
+func NewEpisodeExtractor(rules ...EpisodeRule) *EpisodeExtractor {
+	return &EpisodeExtractor{rules: rules}
+}
+
+// DefaultEpisodeExtractor is used by the import pipeline. It may be replaced
+// before importing to customize the extraction.
+var DefaultEpisodeExtractor = NewEpisodeExtractor(DefaultEpisodeRules...)
+
+// Extract returns the series information of the title. If a rule matched but
+// didn't name the series, the topic is used as series, since the broadcasters
+// file the episodes of a series under its name.
+func (x *EpisodeExtractor) Extract(title, topic string) (Episode, bool) {
+	var result Episode
+	found := false
+
+	rest := title
+	for _, rule := range x.rules {
+		info, r, ok := rule.Extract(rest)
+		if !ok {
+			continue
+		}
+		found = true
+		rest = r
+
+		if result.Series == "" {
+			result.Series = info.Series
+		}
+		if result.Season == 0 {
+			result.Season = info.Season
+		}
+		if result.Number == 0 {
+			result.Number = info.Number
+		}
+		if result.Part == 0 {
+			result.Part = info.Part
+		}
+	}
+
+	if !found {
+		return result, false
+	}
+
+	if result.Series == "" {
+		result.Series = topic
+	}
+
+	return result, true
+}
+
+// extractEpisodes populates the series information of the movie entries. It
+// runs after the channels and topics have been populated, since the topic
+// serves as fallback for the series name.
+func extractEpisodes(x *EpisodeExtractor, entries []movieEntry) {
+	for i := range entries {
+		if info, ok := x.Extract(entries[i].title, entries[i].topic); ok {
+			entries[i].episode = info
+		}
+	}
+}
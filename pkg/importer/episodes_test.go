@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultEpisodeRules(t *testing.T) {
+	x := NewEpisodeExtractor(DefaultEpisodeRules...)
+
+	tests := []struct {
+		title string
+		want  Episode
+		ok    bool
+	}{
+		{"Der Fall Holdt (S03/E05)", Episode{Series: "Krimi", Season: 3, Number: 5}, true},
+		{"Babylon Berlin S3 E12", Episode{Series: "Krimi", Season: 3, Number: 12}, true},
+		{"Babylon Berlin s02|e07", Episode{Series: "Krimi", Season: 2, Number: 7}, true},
+		{"Staffel 2, Folge 12: Abschied", Episode{Series: "Krimi", Season: 2, Number: 12}, true},
+		{"Folge 12", Episode{Series: "Krimi", Number: 12}, true},
+		{"Episode 3 - Der Anfang", Episode{Series: "Krimi", Number: 3}, true},
+		{"Ep.7 Heimkehr", Episode{Series: "Krimi", Number: 7}, true},
+		{"Die Wannseekonferenz, Teil 2", Episode{Series: "Krimi", Part: 2}, true},
+		{"Die Wannseekonferenz (1/3)", Episode{Series: "Krimi", Part: 1}, true},
+		{"Folge 4 (2/2)", Episode{Series: "Krimi", Number: 4, Part: 2}, true},
+		{"Tagesschau 20:00 Uhr", Episode{}, false},
+		{"Die Folgen des Klimawandels", Episode{}, false},
+		{"Das Erbe der Teilung", Episode{}, false},
+		{"Sendung vom 24/7", Episode{}, false},
+		{"Neues aus 2023", Episode{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := x.Extract(tt.title, "Krimi")
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Extract(%q) = %+v, %v, want %+v, %v", tt.title, got, ok,
+				tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEpisodeExtractorSeries(t *testing.T) {
+	x := NewEpisodeExtractor(
+		NewRegexpRule(`^(?P<series>[^:]+):\s*Folge\s+(?P<episode>\d+)`),
+		NewRegexpRule(`(?i)\bTeil\s+(?P<part>\d+)\b`),
+	)
+
+	tests := []struct {
+		title, topic string
+		want         Episode
+	}{
+		// The series named by the title wins over the topic
+		{"Der Schwarzwaldhof: Folge 3", "Filme",
+			Episode{Series: "Der Schwarzwaldhof", Number: 3}},
+		// Without series in the title the topic is used
+		{"Abschied, Teil 2", "Der Schwarzwaldhof",
+			Episode{Series: "Der Schwarzwaldhof", Part: 2}},
+		// A later rule doesn't override the fields of an earlier one
+		{"Der Schwarzwaldhof: Folge 3 - Teil 1", "Filme",
+			Episode{Series: "Der Schwarzwaldhof", Number: 3, Part: 1}},
+	}
+
+	for _, tt := range tests {
+		got, ok := x.Extract(tt.title, tt.topic)
+		if !ok || got != tt.want {
+			t.Errorf("Extract(%q, %q) = %+v, %v, want %+v", tt.title, tt.topic,
+				got, ok, tt.want)
+		}
+	}
+}
+
+func TestRegexpRuleRemovesMatch(t *testing.T) {
+	rule := DefaultEpisodeRules[0]
+
+	info, rest, ok := rule.Extract("Der Fall Holdt (S03/E05) Krimi")
+	if !ok || info.Season != 3 || info.Number != 5 {
+		t.Fatalf("Extract() = %+v, %v", info, ok)
+	}
+	if strings.Contains(rest, "S03") || !strings.HasPrefix(rest, "Der Fall Holdt") {
+		t.Errorf("rest = %q", rest)
+	}
+
+	if _, rest, ok := rule.Extract("Tatort"); ok || rest != "Tatort" {
+		t.Errorf("Extract(Tatort) = %q, %v, want no match", rest, ok)
+	}
+}
@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tschokko/mdthk-api/pkg/moviecat"
+)
+
+// parseGeo converts the geo restriction of a movie entry, e.g. "DE-AT-CH",
+// into a sorted set of ISO 3166-1 country codes. EU is expanded to its member
+// states. An empty restriction or "WELT" means the movie is available
+// worldwide, which results in an empty set.
+func parseGeo(geo string) ([]string, error) {
+	geo = strings.ToUpper(strings.TrimSpace(geo))
+	if geo == "" {
+		return nil, nil
+	}
+
+	set := make(map[string]bool)
+	for _, code := range strings.FieldsFunc(geo, isGeoSeparator) {
+		switch {
+		case code == "EU":
+			for _, c := range moviecat.EUCountries {
+				set[c] = true
+			}
+		case moviecat.IsWorldwide(code):
+			return nil, nil
+		case len(code) == 2 && isUpperLetters(code):
+			set[code] = true
+		default:
+			return nil, fmt.Errorf("invalid country code %q", code)
+		}
+	}
+
+	result := make([]string, 0, len(set))
+	for code := range set {
+		result = append(result, code)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func isGeoSeparator(r rune) bool {
+	return r == '-' || r == ',' || r == ' '
+}
+
+func isUpperLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tschokko/mdthk-api/pkg/moviecat"
+)
+
+func TestParseGeo(t *testing.T) {
+	withCH := append(append([]string(nil), moviecat.EUCountries...), "CH")
+
+	tests := []struct {
+		geo     string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"  ", nil, false},
+		{"DE", []string{"DE"}, false},
+		{"DE-AT-CH", []string{"AT", "CH", "DE"}, false},
+		{"de-at", []string{"AT", "DE"}, false},
+		{"DE,AT CH", []string{"AT", "CH", "DE"}, false},
+		{"DE - AT,,CH", []string{"AT", "CH", "DE"}, false},
+		{"DE-DE", []string{"DE"}, false},
+		{"EU", moviecat.EUCountries, false},
+		{"EU-CH", withCH, false},
+		{"EU-DE-AT", moviecat.EUCountries, false},
+		{"WELT", nil, false},
+		{"weltweit", nil, false},
+		{"DE-WELT", nil, false},
+		{"DEU", nil, true},
+		{"D", nil, true},
+		{"DE-A1", nil, true},
+		{"DE-ÖS", nil, true},
+		{"DE/AT", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGeo(tt.geo)
+		if tt.wantErr {
+			if err == nil || !strings.HasPrefix(err.Error(), "invalid country code") {
+				t.Errorf("parseGeo(%q) error = %v, want invalid country code",
+					tt.geo, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseGeo(%q) error = %v", tt.geo, err)
+			continue
+		}
+		if len(got) != 0 || len(tt.want) != 0 {
+			want := append([]string(nil), tt.want...)
+			sort.Strings(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parseGeo(%q) = %v, want %v", tt.geo, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+)
+
+// nameIDBits limits the IDs derived from names to 53 bits, so they survive
+// the conversion to a JavaScript number.
+const nameIDBits = 53
+
+// nameID derives the ID of a channel or topic from its name. Unlike sequential
+// IDs it doesn't change between imports, so URLs referring to a topic, e.g.
+// feeds, remain valid. Zero isn't used as ID, since it means no restriction in
+// movie filters.
+func nameID(name string) int64 {
+	sum := md5.Sum([]byte(name))
+	id := int64(binary.BigEndian.Uint64(sum[:8]) >> (64 - nameIDBits))
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// assignNameID returns the ID of the name and adds it to the map of known IDs.
+// In the unlikely case of a collision the next free ID is used.
+func assignNameID(name string, ids map[string]int64, used map[int64]bool) int64 {
+	if id, ok := ids[name]; ok {
+		return id
+	}
+
+	id := nameID(name)
+	for used[id] {
+		id = (id + 1) & (1<<nameIDBits - 1)
+		if id == 0 {
+			id = 1
+		}
+	}
+	ids[name] = id
+	used[id] = true
+
+	return id
+}
+
+// stableMovieID identifies a movie across imports by its channel, topic, title
+// and broadcast time. Movies without broadcast time are identified by their
+// URL instead. The database ID of a movie is assigned in list order and
+// changes with every import.
+func stableMovieID(entry movieEntry) string {
+	h := md5.New()
+	for _, s := range []string{entry.channel, entry.topic, entry.title} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	if entry.publishedAt.IsZero() {
+		h.Write([]byte(entry.url))
+	} else {
+		h.Write([]byte(strconv.FormatInt(entry.publishedAt.Unix(), 10)))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
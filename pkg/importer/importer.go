@@ -1,15 +1,148 @@
 package importer
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
+
+// Result describes an imported or verified movie list.
+type Result struct {
+	Hash          string       `json:"hash"`
+	Version       string       `json:"version"`
+	PublishedAt   time.Time    `json:"publishedAt"`
+	Skipped       bool         `json:"skipped,omitempty"`
+	ChannelsCount int          `json:"channelsCount"`
+	TopicsCount   int          `json:"topicsCount"`
+	MoviesCount   int          `json:"moviesCount"`
+	Diagnostics   []Diagnostic `json:"diagnostics,omitempty"`
+}
 
 // ImportMovieList parses the given byte stream and extracts and saves the data
 // into the given SQL database. Based on the extracted meta data, the import
 // function checks if the movie list is already imported, before running the
-// whole import process. If the force flag is true this check will be skipped.
-func ImportMovieList(db *sql.DB, raw []byte, force bool) error {
+// whole import process. If the force flag is true this check will be skipped,
+// unless the movie list is the current catalog, which results in
+// ErrCatalogCurrent. Each movie list is stored in its own schema and
+// registered in the catalog registry. It doesn't become the current catalog,
+// see ActivateCatalog.
+func ImportMovieList(db *sql.DB, raw []byte, force bool) (Result, error) {
+	var result Result
+	str := string(raw)
+
+	meta, err := unmarshalMetaDataEntry(str)
+	if err != nil {
+		return result, err
+	}
+	result.Hash = meta.md5Hash
+	result.Version = meta.version
+	result.PublishedAt = meta.publishedAt
+
+	err = createRegistry(db)
+	if err != nil {
+		return result, err
+	}
+
+	// Importing drops the schema of the movie list first, which must never
+	// happen to the schema served by the API
+	if force {
+		current, err := isCurrentCatalog(db, meta.md5Hash)
+		if err != nil {
+			return result, err
+		}
+		if current {
+			return result, ErrCatalogCurrent
+		}
+	} else {
+		exists, err := movieListExists(db, meta.md5Hash)
+		if err != nil {
+			return result, err
+		}
+		if exists {
+			result.Skipped = true
+			return result, nil
+		}
+	}
+
+	start := time.Now()
+	channels, topics, movies, diags, err := unmarshalMovieImportSource(str)
+	if err != nil {
+		return result, err
+	}
+	result.ChannelsCount = len(channels)
+	result.TopicsCount = len(topics)
+	result.MoviesCount = len(movies)
+	result.Diagnostics = diags
+	observeStage(stageParse, start)
+
+	for _, d := range diags {
+		parseWarnings.WithLabelValues(d.Column).Inc()
+	}
+
+	start = time.Now()
+	err = createAndPrepareSchema(db, meta.md5Hash)
+	if err != nil {
+		return result, err
+	}
+
+	err = bulkCopyChannelEntries(db, meta.md5Hash, channels)
+	if err != nil {
+		return result, err
+	}
+
+	err = bulkCopyTopicEntries(db, meta.md5Hash, topics)
+	if err != nil {
+		return result, err
+	}
+
+	err = bulkCopyMovieEntries(db, meta.md5Hash, movies)
+	if err != nil {
+		return result, err
+	}
+
+	observeStage(stageCopy, start)
+
+	start = time.Now()
+	err = createIndices(db, meta.md5Hash)
+	if err != nil {
+		return result, err
+	}
+	observeStage(stageIndex, start)
+
+	err = registerMovieList(db, meta, len(channels), len(topics), len(movies))
+	if err != nil {
+		return result, err
+	}
+
+	rowsImported.WithLabelValues("channels").Add(float64(len(channels)))
+	rowsImported.WithLabelValues("topics").Add(float64(len(topics)))
+	rowsImported.WithLabelValues("movies").Add(float64(len(movies)))
+	lastSuccess.SetToCurrentTime()
+
+	return result, nil
+}
+
+// VerifyMovieList parses the given byte stream like ImportMovieList does, but
+// doesn't touch any database. The result contains all diagnostics found.
+func VerifyMovieList(raw []byte) (Result, error) {
+	var result Result
 	str := string(raw)
 
-	unmarshalMetaDataEntry(str)
+	meta, err := unmarshalMetaDataEntry(str)
+	if err != nil {
+		return result, err
+	}
+	result.Hash = meta.md5Hash
+	result.Version = meta.version
+	result.PublishedAt = meta.publishedAt
+
+	channels, topics, movies, diags, err := unmarshalMovieImportSource(str)
+	if err != nil {
+		return result, err
+	}
+	result.ChannelsCount = len(channels)
+	result.TopicsCount = len(topics)
+	result.MoviesCount = len(movies)
+	result.Diagnostics = diags
 
-	return nil
+	return result, nil
 }
@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Import stages reported by the stage duration metric
+const (
+	stageDownload   = "download"
+	stageDecompress = "decompress"
+	stageParse      = "parse"
+	stageCopy       = "copy"
+	stageIndex      = "index"
+	stageSwitch     = "switch"
+)
+
+var (
+	stageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "mdthk",
+		Subsystem: "import",
+		Name:      "stage_duration_seconds",
+		Help:      "Duration of the import stages.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"stage"})
+
+	rowsImported = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "mdthk",
+		Subsystem: "import",
+		Name:      "rows_total",
+		Help:      "Number of rows imported by table.",
+	}, []string{"table"})
+
+	parseWarnings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "mdthk",
+		Subsystem: "import",
+		Name:      "parse_warnings_total",
+		Help:      "Number of malformed values found by column.",
+	}, []string{"column"})
+
+	lastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "mdthk",
+		Subsystem: "import",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful import.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(stageDuration, rowsImported, parseWarnings,
+		lastSuccess)
+}
+
+// observeStage records the duration of the stage started at the given time.
+func observeStage(stage string, start time.Time) {
+	observeStageDuration(stage, time.Since(start))
+}
+
+// observeStageDuration records the given duration of the stage.
+func observeStageDuration(stage string, d time.Duration) {
+	stageDuration.WithLabelValues(stage).Observe(d.Seconds())
+}
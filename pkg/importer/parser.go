@@ -6,10 +6,14 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata" // Embed the zoneinfo for Europe/Berlin
+
+	"github.com/tschokko/mdthk-api/pkg/moviecat"
 )
 
 const (
-	colMetaDataPublishedAt = 1
+	colMetaDataPublishedAt = 0
+	colMetaDataUTCDate     = 1
 	colMetaDataVersion     = 2
 	colMetaDataMD5Hash     = 4
 	colChannel             = 0
@@ -31,6 +35,38 @@ const (
 	colIsNew               = 19
 )
 
+// Diagnostic describes a malformed value found in the import source. The
+// importer doesn't stop on such values, but leaves the corresponding field
+// empty.
+type Diagnostic struct {
+	Entry   int    `json:"entry"`
+	Column  string `json:"column"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("entry %d: %s %q: %s", d.Entry, d.Column, d.Value,
+		d.Message)
+}
+
+// unixDateTolerance is the maximum difference between the broadcast date and
+// time and the unix date of a movie entry, before the unix date is preferred.
+const unixDateTolerance = time.Minute
+
+// berlin is the time zone of all local dates in the import source. The
+// zoneinfo is embedded into the binary by importing time/tzdata, so the
+// importer doesn't depend on the zoneinfo of the host.
+var berlin = mustLoadLocation("Europe/Berlin")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
 type metaDataEntry struct {
 	publishedAt time.Time
 	version     string
@@ -38,13 +74,15 @@ type metaDataEntry struct {
 }
 
 type movieEntry struct {
+	stableID       string
+	groupID        string
 	channel        string
 	channelID      int64
 	topic          string
 	topicID        int64
 	title          string
 	publishedAt    time.Time
-	duration       string
+	duration       int64
 	size           uint64
 	descr          string
 	url            string
@@ -55,7 +93,11 @@ type movieEntry struct {
 	unixDate       uint64
 	historyURL     string
 	geo            string
+	geoCodes       []string
 	isNew          bool
+	episode        Episode
+	variant        variantFlags
+	baseID         string
 }
 
 // unmarshalMetaDataEntry extracts the meta data entry of the import source.
@@ -117,9 +159,15 @@ func unmarshalMetaDataEntry(str string) (metaDataEntry, error) {
 		return result, fmt.Errorf("unexpected meta data values")
 	}
 
-	// Parse the published at timestamp
-	result.publishedAt, err = time.Parse("02.01.2006, 15:04",
-		vals[colMetaDataPublishedAt].(string))
+	// Parse the published at timestamp. The meta data contains the German
+	// local time followed by the UTC time. Fall back to the latter if the
+	// local time is malformed.
+	result.publishedAt, err = time.ParseInLocation("02.01.2006, 15:04",
+		vals[colMetaDataPublishedAt].(string), berlin)
+	if err != nil {
+		result.publishedAt, err = time.ParseInLocation("02.01.2006, 15:04",
+			vals[colMetaDataUTCDate].(string), time.UTC)
+	}
 	if err != nil {
 		return result, fmt.Errorf("invalied published at date in meta data")
 	}
@@ -260,27 +308,37 @@ func stripAndValidateSuffix(str string) string {
 }
 
 // unmarshalMovieImportSource parses the import source for channel, topic and
-// movie entries.
-func unmarshalMovieImportSource(str string) (map[string]int64, map[string]int64, []movieEntry, error) {
+// movie entries. Malformed values are reported as diagnostics.
+func unmarshalMovieImportSource(str string) (map[string]int64, map[string]int64, []movieEntry, []Diagnostic, error) {
 	var result []movieEntry
+	var diags []Diagnostic
 	entries, err := extractMovieEntries(str)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
-	for _, e := range entries {
+	for n, e := range entries {
 		var vals []interface{}
 
 		err := json.Unmarshal([]byte(e), &vals)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
+		}
+
+		entry, entryDiags := buildMovieEntry(vals)
+		for _, d := range entryDiags {
+			d.Entry = n
+			diags = append(diags, d)
 		}
-		result = append(result, buildMovieEntry(vals))
+		result = append(result, entry)
 	}
 
 	channels, topics := populateChannelsAndTopics(&result)
+	extractEpisodes(DefaultEpisodeExtractor, result)
+	linkVariants(result)
+	assignGroups(result)
 
-	return channels, topics, result, nil
+	return channels, topics, result, diags, nil
 }
 
 // populateChannelsAndTopics iterates over all movie entries and populates empty
@@ -289,18 +347,19 @@ func unmarshalMovieImportSource(str string) (map[string]int64, map[string]int64,
 // are empty until a new channel or topic starts.
 // Additionally we're creating a channel and topic map which contains a unique
 // ID for each channel and topic. This ID is then applied to the movie entry,
-// too. You can do that all with SQL operations, but applying all IDs in the
+// too. The IDs are derived from the names, see nameID, so they remain stable
+// across imports. Finally each movie gets its stable ID, see stableMovieID.
+// You can do that all with SQL operations, but applying all IDs in the
 // database tooks more than 60s. This approach consumes only a few seconds.
 func populateChannelsAndTopics(entries *[]movieEntry) (map[string]int64, map[string]int64) {
 	var channel, topic string
 	var channels map[string]int64
 	var topics map[string]int64
-	var channelsLastID, topicsLastID int64
 
 	channels = make(map[string]int64)
 	topics = make(map[string]int64)
-	channelsLastID = 1
-	topicsLastID = 1
+	usedChannelIDs := make(map[int64]bool)
+	usedTopicIDs := make(map[int64]bool)
 
 	for i := 0; i < len(*entries); i++ {
 		if (*entries)[i].channel == "" {
@@ -309,10 +368,7 @@ func populateChannelsAndTopics(entries *[]movieEntry) (map[string]int64, map[str
 			channel = (*entries)[i].channel
 
 			// Add new channel to map if not exists
-			if _, ok := channels[channel]; !ok {
-				channels[channel] = channelsLastID
-				channelsLastID++
-			}
+			assignNameID(channel, channels, usedChannelIDs)
 		}
 
 		if (*entries)[i].topic == "" {
@@ -321,15 +377,13 @@ func populateChannelsAndTopics(entries *[]movieEntry) (map[string]int64, map[str
 			topic = (*entries)[i].topic
 
 			// Add new topic to map if not exists
-			if _, ok := topics[topic]; !ok {
-				topics[topic] = topicsLastID
-				topicsLastID++
-			}
+			assignNameID(topic, topics, usedTopicIDs)
 		}
 
 		// Update channel and topic ID
 		(*entries)[i].channelID = channels[(*entries)[i].channel]
 		(*entries)[i].topicID = topics[(*entries)[i].topic]
+		(*entries)[i].stableID = stableMovieID((*entries)[i])
 	}
 
 	return channels, topics
@@ -337,13 +391,23 @@ func populateChannelsAndTopics(entries *[]movieEntry) (map[string]int64, map[str
 
 // buildMovieEntry creates a movieEntry from a list of values. The import source
 // contains only JSON arrays for each movie. The order of the array elements
-// matches a specified attribute.
+// matches a specified attribute. Values which can't be parsed are reported as
+// diagnostics without the entry index.
 // TODO: For backward compatibility observe the version in the meta data of the
 // import source.
-func buildMovieEntry(vals []interface{}) movieEntry {
+func buildMovieEntry(vals []interface{}) (movieEntry, []Diagnostic) {
 	var result movieEntry
+	var diags []Diagnostic
 	var dt, tm string
 
+	report := func(column, value string, err error) {
+		diags = append(diags, Diagnostic{
+			Column:  column,
+			Value:   value,
+			Message: err.Error(),
+		})
+	}
+
 	for i, v := range vals {
 		switch i {
 		case colChannel:
@@ -361,12 +425,18 @@ func buildMovieEntry(vals []interface{}) movieEntry {
 		case colTime:
 			tm = strings.Trim(v.(string), " ")
 		case colDuration:
-			result.duration = strings.Trim(v.(string), " ")
+			duration, err := parseDuration(strings.Trim(v.(string), " "))
+			if err != nil {
+				report("duration", v.(string), err)
+			}
+			result.duration = duration
 			break
 		case colSize:
 			size, err := strconv.ParseUint(strings.Trim(v.(string), " "), 10, 64)
 			if err == nil {
 				result.size = size
+			} else if strings.Trim(v.(string), " ") != "" {
+				report("size", v.(string), err)
 			}
 			break
 		case colDescr:
@@ -391,6 +461,8 @@ func buildMovieEntry(vals []interface{}) movieEntry {
 			unixDate, err := strconv.ParseUint(strings.Trim(v.(string), " "), 10, 64)
 			if err == nil {
 				result.unixDate = unixDate
+			} else if strings.Trim(v.(string), " ") != "" {
+				report("unixDate", v.(string), err)
 			}
 			break
 		case colHistoryURL:
@@ -398,6 +470,11 @@ func buildMovieEntry(vals []interface{}) movieEntry {
 			break
 		case colGeo:
 			result.geo = strings.Trim(v.(string), " ")
+			geoCodes, err := parseGeo(result.geo)
+			if err != nil {
+				report("geo", v.(string), err)
+			}
+			result.geoCodes = geoCodes
 			break
 		case colIsNew:
 			isNew, err := strconv.ParseBool(strings.Trim(v.(string), " "))
@@ -408,45 +485,85 @@ func buildMovieEntry(vals []interface{}) movieEntry {
 		}
 	}
 
-	publishedAt, err := time.Parse("02.01.2006 15:04", dt+" "+tm)
+	publishedAt, err := parseBroadcastTime(dt, tm)
+	if err != nil && dt != "" {
+		report("date", dt+" "+tm, err)
+	}
+
+	// Cross-check the broadcast time with the unix date if present. The unix
+	// date doesn't suffer from any time zone issues, so it's preferred.
+	if result.unixDate > 0 {
+		unixDate := time.Unix(int64(result.unixDate), 0).In(berlin)
+		if err == nil && absDuration(publishedAt.Sub(unixDate)) > unixDateTolerance {
+			report("date", dt+" "+tm,
+				fmt.Errorf("differs from unix date %d", result.unixDate))
+		}
+		publishedAt = unixDate
+		err = nil
+	}
+
 	if err == nil {
 		result.publishedAt = publishedAt
 	}
 
-	return result
+	return result, diags
 }
 
-// convertToFullURL builds proper URLs from the given data in the import source.
-// Most URLs in the import source are cutted down to the modified portion
-// compared to the main / base URL, which is a full URL.
-// E.g. the full base URL is "http://.../abc.mp4" and the cutted down HD format
-// URL is "100|def.mp4". That means from position 100 in the base URL replace
-// everything with the string given after the dash.
-// If the non base URLs doesn't contain a dash, than a regular (full) URL is
-// set or none.
-func convertToFullURL(baseURL, url string) string {
-	var result = strings.Trim(url, " ")
-	if result == "" || baseURL == "" {
-		return result
+// parseBroadcastTime parses the broadcast date and time of a movie entry. Both
+// are given in German local time. The seconds of the time are optional.
+func parseBroadcastTime(dt, tm string) (time.Time, error) {
+	if len(tm) == len("15:04") {
+		tm += ":00"
 	}
 
-	// Check if there's a dash with an index, otherwise return the given URL
-	i := strings.Index(result, "|")
-	if i == -1 {
-		return result
+	return time.ParseInLocation("02.01.2006 15:04:05", dt+" "+tm, berlin)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
 	}
+	return d
+}
 
-	// Get the index for the baseURL
-	j, err := strconv.Atoi(url[:i])
-	if err != nil {
-		return ""
+// parseDuration converts a duration of the form "HH:MM:SS" into seconds. The
+// hours aren't limited to 24, so that long broadcasts are parsed properly. An
+// empty string is a valid value and results in zero seconds.
+func parseDuration(str string) (int64, error) {
+	if str == "" {
+		return 0, nil
 	}
-	if j >= len(baseURL) {
-		return ""
+
+	parts := strings.Split(str, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid duration format")
 	}
 
-	// Get URL path until index
-	result = baseURL[:j] + url[i+1:]
+	var vals [3]int64
+	for i, part := range parts {
+		val, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || val < 0 {
+			return 0, fmt.Errorf("invalid duration format")
+		}
+		vals[i] = val
+	}
 
-	return result
+	if vals[1] >= 60 || vals[2] >= 60 {
+		return 0, fmt.Errorf("invalid duration minutes or seconds")
+	}
+
+	return vals[0]*3600 + vals[1]*60 + vals[2], nil
+}
+
+// convertToFullURL builds proper URLs from the given data in the import source.
+// Most URLs in the import source are cutted down to the modified portion
+// compared to the main / base URL, which is a full URL.
+// E.g. the full base URL is "http://.../abc.mp4" and the cutted down HD format
+// URL is "100|def.mp4". That means from position 100 in the base URL replace
+// everything with the string given after the dash.
+// If the non base URLs doesn't contain a dash, than a regular (full) URL is
+// set or none. The decoding is shared with the exports, see
+// moviecat.ExpandURL.
+func convertToFullURL(baseURL, url string) string {
+	return moviecat.ExpandURL(baseURL, url)
 }
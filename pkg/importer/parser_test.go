@@ -0,0 +1,256 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/moviecat"
+)
+
+func TestConvertToFullURLExpandsCompactURLs(t *testing.T) {
+	const base = "http://download.media.tagesschau.de/video/2024/0301/TV-20240301-2015-2400.webm.h264.mp4"
+
+	urls := []string{
+		"",
+		"http://download.media.tagesschau.de/video/2024/0301/TV-20240301-2015-2400.webs.h264.mp4",
+		"http://download.media.tagesschau.de/video/2024/0301/TV-20240301-2015-2400.webxl.h264.mp4",
+		"https://www.tagesschau.de/multimedia/video/video-1234~subtitle.html",
+		"http://www.tagesschau.de/a|b",
+	}
+
+	for _, url := range urls {
+		compact := moviecat.CompactURL(base, url)
+		if got := convertToFullURL(base, compact); got != url {
+			t.Errorf("convertToFullURL(%q) = %q, want %q", compact, got, url)
+		}
+	}
+}
+
+// testMovieVals returns the values of a movie entry in the import source with
+// the given columns set and all others empty.
+func testMovieVals(cols map[int]string) []interface{} {
+	vals := make([]interface{}, colIsNew+1)
+	for i := range vals {
+		vals[i] = cols[i]
+	}
+	return vals
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    int64
+		wantErr string
+	}{
+		{"", 0, ""},
+		{"00:00:00", 0, ""},
+		{"00:01:30", 90, ""},
+		{"01:02:03", 3723, ""},
+		{"23:59:59", 86399, ""},
+		{"24:00:00", 86400, ""},
+		{"100:00:01", 360001, ""},
+		{"01:02", 0, "invalid duration format"},
+		{"01:02:03:04", 0, "invalid duration format"},
+		{"1h02m03s", 0, "invalid duration format"},
+		{"aa:bb:cc", 0, "invalid duration format"},
+		{"01::03", 0, "invalid duration format"},
+		{"-01:02:03", 0, "invalid duration format"},
+		{"01:60:00", 0, "invalid duration minutes or seconds"},
+		{"01:00:60", 0, "invalid duration minutes or seconds"},
+		{"01:99:99", 0, "invalid duration minutes or seconds"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDuration(tt.str)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("parseDuration(%q) error = %v, want %q", tt.str, err,
+					tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDuration(%q) error = %v", tt.str, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDuration(%q) = %d, want %d", tt.str, got, tt.want)
+		}
+	}
+}
+
+func TestBuildMovieEntryReportsDuration(t *testing.T) {
+	tests := []struct {
+		str      string
+		want     int64
+		wantDiag string
+	}{
+		{" 00:45:00 ", 2700, ""},
+		{"25:00:00", 90000, ""},
+		{"", 0, ""},
+		{"45 min", 0, "invalid duration format"},
+		{"00:45:61", 0, "invalid duration minutes or seconds"},
+	}
+
+	for _, tt := range tests {
+		entry, diags := buildMovieEntry(testMovieVals(map[int]string{
+			colDuration: tt.str}))
+		if entry.duration != tt.want {
+			t.Errorf("duration of %q = %d, want %d", tt.str, entry.duration,
+				tt.want)
+		}
+		if tt.wantDiag == "" {
+			if len(diags) != 0 {
+				t.Errorf("diagnostics of %q = %v, want none", tt.str, diags)
+			}
+			continue
+		}
+		want := Diagnostic{Column: "duration", Value: tt.str,
+			Message: tt.wantDiag}
+		if len(diags) != 1 || diags[0] != want {
+			t.Errorf("diagnostics of %q = %v, want [%v]", tt.str, diags, want)
+		}
+	}
+}
+
+func TestParseBroadcastTime(t *testing.T) {
+	tests := []struct {
+		dt, tm string
+		want   time.Time
+	}{
+		// Central European Time in winter
+		{"15.01.2024", "20:15:00", time.Date(2024, 1, 15, 19, 15, 0, 0, time.UTC)},
+		// Central European Summer Time
+		{"15.07.2024", "20:15:00", time.Date(2024, 7, 15, 18, 15, 0, 0, time.UTC)},
+		// Time without seconds
+		{"15.07.2024", "20:15", time.Date(2024, 7, 15, 18, 15, 0, 0, time.UTC)},
+		// Right after the switch to summer time
+		{"31.03.2024", "03:00:00", time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := parseBroadcastTime(tt.dt, tt.tm)
+		if err != nil {
+			t.Errorf("parseBroadcastTime(%q, %q) error = %v", tt.dt, tt.tm, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseBroadcastTime(%q, %q) = %s, want %s", tt.dt, tt.tm,
+				got.UTC(), tt.want)
+		}
+	}
+
+	for _, tm := range []string{"20", "20:15:00:00", "8:15 PM"} {
+		if _, err := parseBroadcastTime("15.01.2024", tm); err == nil {
+			t.Errorf("parseBroadcastTime(%q) succeeded", tm)
+		}
+	}
+}
+
+func TestBuildMovieEntryCrossChecksUnixDate(t *testing.T) {
+	broadcast := time.Date(2024, 7, 15, 18, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		unixDate time.Time
+		wantDiag bool
+	}{
+		{"equal", broadcast, false},
+		{"within tolerance", broadcast.Add(unixDateTolerance), false},
+		{"before within tolerance", broadcast.Add(-unixDateTolerance), false},
+		{"beyond tolerance", broadcast.Add(unixDateTolerance + time.Second), true},
+		{"off by an hour", broadcast.Add(-time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unixDate := strconv.FormatInt(tt.unixDate.Unix(), 10)
+			entry, diags := buildMovieEntry(testMovieVals(map[int]string{
+				colDate:     "15.07.2024",
+				colTime:     "20:15:00",
+				colUnixDate: unixDate,
+			}))
+
+			// The unix date is preferred in any case
+			if !entry.publishedAt.Equal(tt.unixDate) {
+				t.Errorf("publishedAt = %s, want %s", entry.publishedAt.UTC(),
+					tt.unixDate)
+			}
+
+			if !tt.wantDiag {
+				if len(diags) != 0 {
+					t.Errorf("diagnostics = %v, want none", diags)
+				}
+				return
+			}
+			want := Diagnostic{Column: "date", Value: "15.07.2024 20:15:00",
+				Message: "differs from unix date " + unixDate}
+			if len(diags) != 1 || diags[0] != want {
+				t.Errorf("diagnostics = %v, want [%v]", diags, want)
+			}
+		})
+	}
+}
+
+func TestBuildMovieEntryFallsBackToUnixDate(t *testing.T) {
+	want := time.Date(2024, 1, 15, 19, 15, 0, 0, time.UTC)
+	entry, diags := buildMovieEntry(testMovieVals(map[int]string{
+		colDate:     "15.01.2024",
+		colTime:     "20.15",
+		colUnixDate: strconv.FormatInt(want.Unix(), 10),
+	}))
+
+	if !entry.publishedAt.Equal(want) {
+		t.Errorf("publishedAt = %s, want %s", entry.publishedAt.UTC(), want)
+	}
+	if len(diags) != 1 || diags[0].Column != "date" {
+		t.Errorf("diagnostics = %v, want malformed date", diags)
+	}
+}
+
+func TestUnmarshalMetaDataEntry(t *testing.T) {
+	const list = `{"Filmliste":[%q,%q,"3","MSearch [Vers.: 3.1.219]","0123456789abcdef"],` +
+		`"Filmliste":["Sender","Thema"],"X":["ARD"]}`
+
+	tests := []struct {
+		name       string
+		local, utc string
+		want       time.Time
+		wantErr    bool
+	}{
+		{"local time in winter", "15.01.2024, 20:15", "15.01.2024, 19:15",
+			time.Date(2024, 1, 15, 19, 15, 0, 0, time.UTC), false},
+		{"local time in summer", "15.07.2024, 20:15", "15.07.2024, 18:15",
+			time.Date(2024, 7, 15, 18, 15, 0, 0, time.UTC), false},
+		{"fallback to UTC", "15.07.2024 20:15", "15.07.2024, 18:15",
+			time.Date(2024, 7, 15, 18, 15, 0, 0, time.UTC), false},
+		{"fallback to UTC on empty local time", "", "15.07.2024, 18:15",
+			time.Date(2024, 7, 15, 18, 15, 0, 0, time.UTC), false},
+		{"both malformed", "", "", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := unmarshalMetaDataEntry(fmt.Sprintf(list, tt.local,
+				tt.utc))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("unmarshalMetaDataEntry() succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !entry.publishedAt.Equal(tt.want) {
+				t.Errorf("publishedAt = %s, want %s", entry.publishedAt.UTC(),
+					tt.want)
+			}
+			if entry.version != "3" || entry.md5Hash != "0123456789abcdef" {
+				t.Errorf("entry = %+v", entry)
+			}
+		})
+	}
+}
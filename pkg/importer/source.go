@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ulikunitz/xz"
+)
+
+// DefaultMovieListURL is the MediathekView mirror serving the full movie list.
+const DefaultMovieListURL = "https://liste.mediathekview.de/Filmliste-akt.xz"
+
+// sourceTimeout bounds the whole download of a movie list from an HTTP(S)
+// source including reading the body. It's generous to allow for slow
+// connections, but keeps a stalled mirror from blocking the daemon forever.
+const sourceTimeout = 10 * time.Minute
+
+// sourceClient fetches movie lists from HTTP(S) sources.
+var sourceClient = &http.Client{Timeout: sourceTimeout}
+
+// xzMagic is the header of xz compressed data
+var xzMagic = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+
+// OpenMovieList opens the movie list at the given source, which is either an
+// HTTP(S) URL, a file path or "-" for stdin. The returned reader yields the
+// raw movie list, xz compressed sources are decompressed on the fly. Reading
+// HTTP(S) sources is aborted once the context is done.
+func OpenMovieList(ctx context.Context, src string) (io.ReadCloser, error) {
+	rc, err := openSource(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := decompress(rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, rc}, nil
+}
+
+// ReadMovieList reads the whole movie list at the given source. See
+// OpenMovieList for the supported sources. The source is decompressed while
+// it's read, the time spent waiting for the source is reported as download
+// stage and the remaining time as decompress stage.
+func ReadMovieList(ctx context.Context, src string) ([]byte, error) {
+	start := time.Now()
+	rc, err := openSource(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	tr := &timedReader{r: rc, elapsed: time.Since(start)}
+	r, err := decompress(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	observeStageDuration(stageDownload, tr.elapsed)
+	observeStageDuration(stageDecompress, time.Since(start)-tr.elapsed)
+
+	return raw, nil
+}
+
+// DownloadMovieList copies the movie list at the given source to w without
+// decompressing it.
+func DownloadMovieList(ctx context.Context, src string, w io.Writer) (int64, error) {
+	rc, err := openSource(ctx, src)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	return io.Copy(w, rc)
+}
+
+func openSource(ctx context.Context, src string) (io.ReadCloser, error) {
+	if src == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := sourceClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to fetch movie list: %s", resp.Status)
+		}
+		return resp.Body, nil
+	}
+
+	return os.Open(src)
+}
+
+// timedReader sums up the time spent reading from the underlying reader.
+type timedReader struct {
+	r       io.Reader
+	elapsed time.Duration
+}
+
+func (t *timedReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := t.r.Read(p)
+	t.elapsed += time.Since(start)
+	return n, err
+}
+
+// decompress checks the data for the xz header. If found, the data is
+// decompressed, otherwise returned as is.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(len(xzMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !bytes.Equal(header, xzMagic) {
+		return br, nil
+	}
+
+	return xz.NewReader(br)
+}
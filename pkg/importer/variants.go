@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"regexp"
+	"time"
+)
+
+// variantFlags marks the accessibility and language variants of a broadcast.
+type variantFlags struct {
+	audioDescription bool
+	signLanguage     bool
+	originalVersion  bool
+}
+
+func (f variantFlags) any() bool {
+	return f.audioDescription || f.signLanguage || f.originalVersion
+}
+
+// variantSuffixes match the title suffixes used by MediathekView to mark the
+// variants, e.g. "Tatort (Audiodeskription)" or "Tatort (Hörfassung)".
+// Abbreviations are only accepted in parentheses, since they are too
+// ambiguous otherwise.
+var variantSuffixes = []struct {
+	pattern *regexp.Regexp
+	set     func(*variantFlags)
+}{
+	{
+		regexp.MustCompile(`(?i)\s*(?:[-–|:]\s*|\(\s*)(?:mit\s+)?(?:Audiodeskription|Hörfassung)\s*\)?\s*$|\s*\(\s*AD\s*\)\s*$`),
+		func(f *variantFlags) { f.audioDescription = true },
+	},
+	{
+		regexp.MustCompile(`(?i)\s*(?:[-–|:]\s*|\(\s*)(?:mit\s+|in\s+)?Gebärdensprache\s*\)?\s*$|\s*\(\s*DGS\s*\)\s*$`),
+		func(f *variantFlags) { f.signLanguage = true },
+	},
+	{
+		regexp.MustCompile(`(?i)\s*(?:[-–|:]\s*|\(\s*)Originalversion(?:\s+mit\s+Untertiteln?)?\s*\)?\s*$|\s*\(\s*(?:OV|OmU)\s*\)\s*$`),
+		func(f *variantFlags) { f.originalVersion = true },
+	},
+}
+
+// parseVariant strips the variant suffixes from the title and returns the
+// title of the base broadcast together with the flags found. Several
+// suffixes may be combined.
+func parseVariant(title string) (string, variantFlags) {
+	var flags variantFlags
+
+	for stripped := true; stripped; {
+		stripped = false
+		for _, s := range variantSuffixes {
+			if loc := s.pattern.FindStringIndex(title); loc != nil && loc[0] > 0 {
+				title = title[:loc[0]]
+				s.set(&flags)
+				stripped = true
+			}
+		}
+	}
+
+	return title, flags
+}
+
+// linkVariants detects the variants among the movie entries and links each of
+// them to its base broadcast. The base broadcast has the same topic and title
+// without suffix. If there are several, e.g. repeats, the one broadcast
+// closest in time is chosen. Variants without base broadcast in the list
+// remain unlinked.
+func linkVariants(entries []movieEntry) {
+	bases := make(map[string][]int)
+	var variants []int
+	var baseTitles []string
+
+	for i := range entries {
+		base, flags := parseVariant(entries[i].title)
+		entries[i].variant = flags
+
+		key := entries[i].topic + "\x00" + normalizeForGrouping(base)
+		if flags.any() {
+			variants = append(variants, i)
+			baseTitles = append(baseTitles, key)
+		} else {
+			bases[key] = append(bases[key], i)
+		}
+	}
+
+	for n, i := range variants {
+		best := -1
+		var bestDiff time.Duration
+		for _, j := range bases[baseTitles[n]] {
+			diff := absDuration(entries[i].publishedAt.Sub(entries[j].publishedAt))
+			if best < 0 || diff < bestDiff {
+				best, bestDiff = j, diff
+			}
+		}
+		if best >= 0 {
+			entries[i].baseID = entries[best].stableID
+		}
+	}
+}
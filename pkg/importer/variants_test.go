@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseVariant(t *testing.T) {
+	tests := []struct {
+		title string
+		base  string
+		flags variantFlags
+	}{
+		{"Tatort: Borowski", "Tatort: Borowski", variantFlags{}},
+		{"Tatort (Audiodeskription)", "Tatort", variantFlags{audioDescription: true}},
+		{"Tatort - mit Audiodeskription", "Tatort", variantFlags{audioDescription: true}},
+		{"Tatort (AD)", "Tatort", variantFlags{audioDescription: true}},
+		{"Tagesschau (mit Gebärdensprache)", "Tagesschau", variantFlags{signLanguage: true}},
+		{"Tagesschau | DGS", "Tagesschau | DGS", variantFlags{}},
+		{"Tagesschau (DGS)", "Tagesschau", variantFlags{signLanguage: true}},
+		{"The Crown (OmU)", "The Crown", variantFlags{originalVersion: true}},
+		{"The Crown - Originalversion mit Untertiteln", "The Crown",
+			variantFlags{originalVersion: true}},
+		// Combined suffixes in any order
+		{"Der Rote Baron (Hörfassung) (OmU)", "Der Rote Baron",
+			variantFlags{audioDescription: true, originalVersion: true}},
+		{"Der Rote Baron (OmU) - Hörfassung", "Der Rote Baron",
+			variantFlags{audioDescription: true, originalVersion: true}},
+		{"Der Rote Baron (Audiodeskription) (OmU)", "Der Rote Baron",
+			variantFlags{audioDescription: true, originalVersion: true}},
+		{"Der Rote Baron (OmU) - Audiodeskription", "Der Rote Baron",
+			variantFlags{audioDescription: true, originalVersion: true}},
+		{"Der Rote Baron (OV) (AD) (DGS)", "Der Rote Baron",
+			variantFlags{audioDescription: true, signLanguage: true, originalVersion: true}},
+		// A title consisting of the suffix only isn't a variant
+		{"Audiodeskription", "Audiodeskription", variantFlags{}},
+	}
+
+	for _, tt := range tests {
+		base, flags := parseVariant(tt.title)
+		if base != tt.base || flags != tt.flags {
+			t.Errorf("parseVariant(%q) = %q, %+v, want %q, %+v", tt.title, base,
+				flags, tt.base, tt.flags)
+		}
+	}
+}
+
+func TestLinkVariants(t *testing.T) {
+	at := func(day int) time.Time {
+		return time.Date(2024, 3, day, 20, 15, 0, 0, berlin)
+	}
+
+	entries := []movieEntry{
+		{stableID: "base-1", topic: "Tatort", title: "Borowski", publishedAt: at(1)},
+		{stableID: "base-10", topic: "Tatort", title: "Borowski", publishedAt: at(10)},
+		{stableID: "base-other", topic: "Polizeiruf", title: "Borowski", publishedAt: at(9)},
+		{stableID: "ad", topic: "Tatort", title: "Borowski (Audiodeskription)", publishedAt: at(8)},
+		{stableID: "ad-omu", topic: "Tatort", title: "Borowski (Hörfassung) (OmU)", publishedAt: at(2)},
+		{stableID: "orphan", topic: "Tatort", title: "Faber (Audiodeskription)", publishedAt: at(8)},
+		{stableID: "other-topic", topic: "Krimi", title: "Borowski (OmU)", publishedAt: at(10)},
+	}
+
+	linkVariants(entries)
+
+	want := map[string]string{
+		"base-1":      "",
+		"base-10":     "",
+		"base-other":  "",
+		"ad":          "base-10",
+		"ad-omu":      "base-1",
+		"orphan":      "",
+		"other-topic": "",
+	}
+	for _, entry := range entries {
+		if entry.baseID != want[entry.stableID] {
+			t.Errorf("base of %s = %q, want %q", entry.stableID, entry.baseID,
+				want[entry.stableID])
+		}
+	}
+
+	if !entries[4].variant.audioDescription || !entries[4].variant.originalVersion {
+		t.Errorf("flags of ad-omu = %+v", entries[4].variant)
+	}
+	if entries[0].variant.any() {
+		t.Errorf("flags of base-1 = %+v", entries[0].variant)
+	}
+}
@@ -0,0 +1,24 @@
+package moviecat
+
+import (
+	"fmt"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// UnmarshalCatalog decodes a movie catalog which was marshalled as a single
+// MovieCatalog message, like the files written before the stream format was
+// introduced. Both format versions are accepted.
+func UnmarshalCatalog(b []byte) (*MovieCatalog, error) {
+	catalog := &MovieCatalog{}
+	err := proto.Unmarshal(b, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	if catalog.Version < 1 || catalog.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported movie catalog version %d", catalog.Version)
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,82 @@
+package moviecat
+
+import (
+	"sort"
+	"strings"
+)
+
+// EUCountries are the member states of the European Union as ISO 3166-1
+// country codes. The movie list uses EU as shorthand for them.
+var EUCountries = []string{
+	"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR",
+	"HR", "HU", "IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO",
+	"SE", "SI", "SK",
+}
+
+// geoRegionCodes maps the geo codes used by the MediathekView movie list to
+// the corresponding regions. The list marks worldwide movies with WELT,
+// WELTWEIT is accepted as alias.
+var geoRegionCodes = map[string]GeoRegion{
+	"DE":       GeoRegion_GEO_REGION_DE,
+	"AT":       GeoRegion_GEO_REGION_AT,
+	"CH":       GeoRegion_GEO_REGION_CH,
+	"EU":       GeoRegion_GEO_REGION_EU,
+	"WELT":     GeoRegion_GEO_REGION_WORLD,
+	"WELTWEIT": GeoRegion_GEO_REGION_WORLD,
+}
+
+// ParseGeoRegions parses a MediathekView geo string like "DE-AT-CH" into its
+// regions. Unknown codes are skipped. An empty string means the movie isn't
+// geo restricted and results in no regions at all.
+func ParseGeoRegions(geo string) []GeoRegion {
+	var result []GeoRegion
+
+	for _, code := range strings.Split(strings.ToUpper(geo), "-") {
+		if region, ok := geoRegionCodes[strings.TrimSpace(code)]; ok {
+			result = append(result, region)
+		}
+	}
+
+	return result
+}
+
+// IsWorldwide checks if the geo code marks a movie as available worldwide.
+func IsWorldwide(code string) bool {
+	return geoRegionCodes[strings.ToUpper(strings.TrimSpace(code))] ==
+		GeoRegion_GEO_REGION_WORLD
+}
+
+// GeoRegionsFromCodes maps a set of ISO 3166-1 country codes, as normalized
+// by the importer, to the regions. If the set contains all EU member states,
+// they are reported as EU. Countries without region are skipped, an empty set
+// results in no regions at all.
+func GeoRegionsFromCodes(codes []string) []GeoRegion {
+	var result []GeoRegion
+
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+
+	eu := len(set) > 0
+	for _, code := range EUCountries {
+		eu = eu && set[code]
+	}
+	if eu {
+		result = append(result, GeoRegion_GEO_REGION_EU)
+	}
+
+	for code := range set {
+		region, ok := geoRegionCodes[code]
+		if !ok || region == GeoRegion_GEO_REGION_WORLD {
+			continue
+		}
+		if eu && (region == GeoRegion_GEO_REGION_DE || region == GeoRegion_GEO_REGION_AT) {
+			continue
+		}
+		result = append(result, region)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+
+	return result
+}
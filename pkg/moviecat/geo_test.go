@@ -0,0 +1,66 @@
+package moviecat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeoRegionsFromCodes(t *testing.T) {
+	withoutDE := make([]string, 0, len(EUCountries))
+	for _, code := range EUCountries {
+		if code != "DE" {
+			withoutDE = append(withoutDE, code)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		codes []string
+		want  []GeoRegion
+	}{
+		{"worldwide", nil, nil},
+		{"DE", []string{"DE"}, []GeoRegion{GeoRegion_GEO_REGION_DE}},
+		{"DACH", []string{"AT", "CH", "DE"}, []GeoRegion{GeoRegion_GEO_REGION_DE,
+			GeoRegion_GEO_REGION_AT, GeoRegion_GEO_REGION_CH}},
+		{"EU", EUCountries, []GeoRegion{GeoRegion_GEO_REGION_EU}},
+		{"EU and CH", append(append([]string(nil), EUCountries...), "CH"),
+			[]GeoRegion{GeoRegion_GEO_REGION_CH, GeoRegion_GEO_REGION_EU}},
+		{"EU without DE", withoutDE, []GeoRegion{GeoRegion_GEO_REGION_AT}},
+		{"without region", []string{"FR", "IT"}, nil},
+		{"WELT", []string{"WELT"}, nil},
+	}
+
+	for _, tt := range tests {
+		got := GeoRegionsFromCodes(tt.codes)
+		if len(got) != 0 || len(tt.want) != 0 {
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s: GeoRegionsFromCodes() = %v, want %v", tt.name, got,
+					tt.want)
+			}
+		}
+	}
+}
+
+func TestParseGeoRegions(t *testing.T) {
+	tests := []struct {
+		geo  string
+		want []GeoRegion
+	}{
+		{"", nil},
+		{"DE-AT-CH", []GeoRegion{GeoRegion_GEO_REGION_DE,
+			GeoRegion_GEO_REGION_AT, GeoRegion_GEO_REGION_CH}},
+		{"eu", []GeoRegion{GeoRegion_GEO_REGION_EU}},
+		{"WELT", []GeoRegion{GeoRegion_GEO_REGION_WORLD}},
+		{"DE - XX", []GeoRegion{GeoRegion_GEO_REGION_DE}},
+	}
+
+	for _, tt := range tests {
+		got := ParseGeoRegions(tt.geo)
+		if len(got) != 0 || len(tt.want) != 0 {
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGeoRegions(%q) = %v, want %v", tt.geo, got,
+					tt.want)
+			}
+		}
+	}
+}
@@ -3,9 +3,15 @@
 
 package moviecat
 
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -16,12 +22,49 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GeoRegion int32
+
+const (
+	GeoRegion_GEO_REGION_UNSPECIFIED GeoRegion = 0
+	GeoRegion_GEO_REGION_DE          GeoRegion = 1
+	GeoRegion_GEO_REGION_AT          GeoRegion = 2
+	GeoRegion_GEO_REGION_CH          GeoRegion = 3
+	GeoRegion_GEO_REGION_EU          GeoRegion = 4
+	GeoRegion_GEO_REGION_WORLD       GeoRegion = 5
+)
+
+var GeoRegion_name = map[int32]string{
+	0: "GEO_REGION_UNSPECIFIED",
+	1: "GEO_REGION_DE",
+	2: "GEO_REGION_AT",
+	3: "GEO_REGION_CH",
+	4: "GEO_REGION_EU",
+	5: "GEO_REGION_WORLD",
+}
+
+var GeoRegion_value = map[string]int32{
+	"GEO_REGION_UNSPECIFIED": 0,
+	"GEO_REGION_DE":          1,
+	"GEO_REGION_AT":          2,
+	"GEO_REGION_CH":          3,
+	"GEO_REGION_EU":          4,
+	"GEO_REGION_WORLD":       5,
+}
+
+func (x GeoRegion) String() string {
+	return proto.EnumName(GeoRegion_name, int32(x))
+}
+
+func (GeoRegion) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{0}
+}
 
 type ChannelEntry struct {
-	Version              int32    `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
-	Id                   int64    `protobuf:"varint,2,opt,name=id" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -31,16 +74,17 @@ func (m *ChannelEntry) Reset()         { *m = ChannelEntry{} }
 func (m *ChannelEntry) String() string { return proto.CompactTextString(m) }
 func (*ChannelEntry) ProtoMessage()    {}
 func (*ChannelEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_moviecat_223631d182a39b34, []int{0}
+	return fileDescriptor_651fdac2fff37738, []int{0}
 }
+
 func (m *ChannelEntry) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelEntry.Unmarshal(m, b)
 }
 func (m *ChannelEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_ChannelEntry.Marshal(b, m, deterministic)
 }
-func (dst *ChannelEntry) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ChannelEntry.Merge(dst, src)
+func (m *ChannelEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelEntry.Merge(m, src)
 }
 func (m *ChannelEntry) XXX_Size() int {
 	return xxx_messageInfo_ChannelEntry.Size(m)
@@ -73,9 +117,9 @@ func (m *ChannelEntry) GetName() string {
 }
 
 type TopicEntry struct {
-	Version              int32    `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
-	Id                   int64    `protobuf:"varint,2,opt,name=id" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -85,16 +129,17 @@ func (m *TopicEntry) Reset()         { *m = TopicEntry{} }
 func (m *TopicEntry) String() string { return proto.CompactTextString(m) }
 func (*TopicEntry) ProtoMessage()    {}
 func (*TopicEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_moviecat_223631d182a39b34, []int{1}
+	return fileDescriptor_651fdac2fff37738, []int{1}
 }
+
 func (m *TopicEntry) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TopicEntry.Unmarshal(m, b)
 }
 func (m *TopicEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TopicEntry.Marshal(b, m, deterministic)
 }
-func (dst *TopicEntry) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TopicEntry.Merge(dst, src)
+func (m *TopicEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicEntry.Merge(m, src)
 }
 func (m *TopicEntry) XXX_Size() int {
 	return xxx_messageInfo_TopicEntry.Size(m)
@@ -127,23 +172,39 @@ func (m *TopicEntry) GetName() string {
 }
 
 type MovieEntry struct {
-	Version              int32    `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
-	Id                   string   `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
-	ChannelId            int64    `protobuf:"varint,3,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
-	TopicId              int64    `protobuf:"varint,4,opt,name=topic_id,json=topicId" json:"topic_id,omitempty"`
-	Title                string   `protobuf:"bytes,5,opt,name=title" json:"title,omitempty"`
-	PublishedAt          int64    `protobuf:"varint,6,opt,name=published_at,json=publishedAt" json:"published_at,omitempty"`
-	Duration             int64    `protobuf:"varint,7,opt,name=duration" json:"duration,omitempty"`
-	Size                 int64    `protobuf:"varint,8,opt,name=size" json:"size,omitempty"`
-	Descr                string   `protobuf:"bytes,9,opt,name=descr" json:"descr,omitempty"`
-	Url                  string   `protobuf:"bytes,10,opt,name=url" json:"url,omitempty"`
-	HasWebsiteUrl        bool     `protobuf:"varint,11,opt,name=has_website_url,json=hasWebsiteUrl" json:"has_website_url,omitempty"`
-	HasSubtitleUrl       bool     `protobuf:"varint,12,opt,name=has_subtitle_url,json=hasSubtitleUrl" json:"has_subtitle_url,omitempty"`
-	HasSmallFormatUrl    bool     `protobuf:"varint,13,opt,name=has_small_format_url,json=hasSmallFormatUrl" json:"has_small_format_url,omitempty"`
-	HasHdFormatUrl       bool     `protobuf:"varint,14,opt,name=has_hd_format_url,json=hasHdFormatUrl" json:"has_hd_format_url,omitempty"`
-	HasHistoryUrl        bool     `protobuf:"varint,15,opt,name=has_history_url,json=hasHistoryUrl" json:"has_history_url,omitempty"`
-	Geo                  string   `protobuf:"bytes,16,opt,name=geo" json:"geo,omitempty"`
-	IsNew                bool     `protobuf:"varint,17,opt,name=is_new,json=isNew" json:"is_new,omitempty"`
+	Version           int32  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Id                string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ChannelId         int64  `protobuf:"varint,3,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	TopicId           int64  `protobuf:"varint,4,opt,name=topic_id,json=topicId,proto3" json:"topic_id,omitempty"`
+	Title             string `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	PublishedAt       int64  `protobuf:"varint,6,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	Duration          int64  `protobuf:"varint,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	Size              int64  `protobuf:"varint,8,opt,name=size,proto3" json:"size,omitempty"`
+	Descr             string `protobuf:"bytes,9,opt,name=descr,proto3" json:"descr,omitempty"`
+	Url               string `protobuf:"bytes,10,opt,name=url,proto3" json:"url,omitempty"`
+	HasWebsiteUrl     bool   `protobuf:"varint,11,opt,name=has_website_url,json=hasWebsiteUrl,proto3" json:"has_website_url,omitempty"`
+	HasSubtitleUrl    bool   `protobuf:"varint,12,opt,name=has_subtitle_url,json=hasSubtitleUrl,proto3" json:"has_subtitle_url,omitempty"`
+	HasSmallFormatUrl bool   `protobuf:"varint,13,opt,name=has_small_format_url,json=hasSmallFormatUrl,proto3" json:"has_small_format_url,omitempty"`
+	HasHdFormatUrl    bool   `protobuf:"varint,14,opt,name=has_hd_format_url,json=hasHdFormatUrl,proto3" json:"has_hd_format_url,omitempty"`
+	HasHistoryUrl     bool   `protobuf:"varint,15,opt,name=has_history_url,json=hasHistoryUrl,proto3" json:"has_history_url,omitempty"`
+	Geo               string `protobuf:"bytes,16,opt,name=geo,proto3" json:"geo,omitempty"`
+	IsNew             bool   `protobuf:"varint,17,opt,name=is_new,json=isNew,proto3" json:"is_new,omitempty"`
+	// Since version 2
+	WebsiteUrl       string      `protobuf:"bytes,18,opt,name=website_url,json=websiteUrl,proto3" json:"website_url,omitempty"`
+	SubtitleUrl      string      `protobuf:"bytes,19,opt,name=subtitle_url,json=subtitleUrl,proto3" json:"subtitle_url,omitempty"`
+	SmallFormatUrl   string      `protobuf:"bytes,20,opt,name=small_format_url,json=smallFormatUrl,proto3" json:"small_format_url,omitempty"`
+	HdFormatUrl      string      `protobuf:"bytes,21,opt,name=hd_format_url,json=hdFormatUrl,proto3" json:"hd_format_url,omitempty"`
+	HistoryUrl       string      `protobuf:"bytes,22,opt,name=history_url,json=historyUrl,proto3" json:"history_url,omitempty"`
+	UnixDate         int64       `protobuf:"varint,23,opt,name=unix_date,json=unixDate,proto3" json:"unix_date,omitempty"`
+	AudioDescription bool        `protobuf:"varint,24,opt,name=audio_description,json=audioDescription,proto3" json:"audio_description,omitempty"`
+	SignLanguage     bool        `protobuf:"varint,25,opt,name=sign_language,json=signLanguage,proto3" json:"sign_language,omitempty"`
+	GeoRegions       []GeoRegion `protobuf:"varint,26,rep,packed,name=geo_regions,json=geoRegions,proto3,enum=moviecat.GeoRegion" json:"geo_regions,omitempty"`
+	OriginalVersion  bool        `protobuf:"varint,27,opt,name=original_version,json=originalVersion,proto3" json:"original_version,omitempty"`
+	// Stable ID of the base broadcast if the entry is an accessibility or
+	// language variant of it.
+	BaseId string `protobuf:"bytes,28,opt,name=base_id,json=baseId,proto3" json:"base_id,omitempty"`
+	// ID of the movie which is stable across catalogs, which base_id refers to.
+	StableId             string   `protobuf:"bytes,29,opt,name=stable_id,json=stableId,proto3" json:"stable_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -153,16 +214,17 @@ func (m *MovieEntry) Reset()         { *m = MovieEntry{} }
 func (m *MovieEntry) String() string { return proto.CompactTextString(m) }
 func (*MovieEntry) ProtoMessage()    {}
 func (*MovieEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_moviecat_223631d182a39b34, []int{2}
+	return fileDescriptor_651fdac2fff37738, []int{2}
 }
+
 func (m *MovieEntry) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MovieEntry.Unmarshal(m, b)
 }
 func (m *MovieEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_MovieEntry.Marshal(b, m, deterministic)
 }
-func (dst *MovieEntry) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MovieEntry.Merge(dst, src)
+func (m *MovieEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MovieEntry.Merge(m, src)
 }
 func (m *MovieEntry) XXX_Size() int {
 	return xxx_messageInfo_MovieEntry.Size(m)
@@ -292,13 +354,97 @@ func (m *MovieEntry) GetIsNew() bool {
 	return false
 }
 
+func (m *MovieEntry) GetWebsiteUrl() string {
+	if m != nil {
+		return m.WebsiteUrl
+	}
+	return ""
+}
+
+func (m *MovieEntry) GetSubtitleUrl() string {
+	if m != nil {
+		return m.SubtitleUrl
+	}
+	return ""
+}
+
+func (m *MovieEntry) GetSmallFormatUrl() string {
+	if m != nil {
+		return m.SmallFormatUrl
+	}
+	return ""
+}
+
+func (m *MovieEntry) GetHdFormatUrl() string {
+	if m != nil {
+		return m.HdFormatUrl
+	}
+	return ""
+}
+
+func (m *MovieEntry) GetHistoryUrl() string {
+	if m != nil {
+		return m.HistoryUrl
+	}
+	return ""
+}
+
+func (m *MovieEntry) GetUnixDate() int64 {
+	if m != nil {
+		return m.UnixDate
+	}
+	return 0
+}
+
+func (m *MovieEntry) GetAudioDescription() bool {
+	if m != nil {
+		return m.AudioDescription
+	}
+	return false
+}
+
+func (m *MovieEntry) GetSignLanguage() bool {
+	if m != nil {
+		return m.SignLanguage
+	}
+	return false
+}
+
+func (m *MovieEntry) GetGeoRegions() []GeoRegion {
+	if m != nil {
+		return m.GeoRegions
+	}
+	return nil
+}
+
+func (m *MovieEntry) GetOriginalVersion() bool {
+	if m != nil {
+		return m.OriginalVersion
+	}
+	return false
+}
+
+func (m *MovieEntry) GetBaseId() string {
+	if m != nil {
+		return m.BaseId
+	}
+	return ""
+}
+
+func (m *MovieEntry) GetStableId() string {
+	if m != nil {
+		return m.StableId
+	}
+	return ""
+}
+
 type MovieCatalog struct {
-	Version              int32           `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
-	PublishedAt          int64           `protobuf:"varint,2,opt,name=published_at,json=publishedAt" json:"published_at,omitempty"`
+	Version              int32           `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PublishedAt          int64           `protobuf:"varint,2,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
 	Md5Hash              []byte          `protobuf:"bytes,3,opt,name=md5_hash,json=md5Hash,proto3" json:"md5_hash,omitempty"`
-	Channels             []*ChannelEntry `protobuf:"bytes,4,rep,name=channels" json:"channels,omitempty"`
-	Topics               []*TopicEntry   `protobuf:"bytes,5,rep,name=topics" json:"topics,omitempty"`
-	Movies               []*MovieEntry   `protobuf:"bytes,6,rep,name=movies" json:"movies,omitempty"`
+	Channels             []*ChannelEntry `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+	Topics               []*TopicEntry   `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty"`
+	Movies               []*MovieEntry   `protobuf:"bytes,6,rep,name=movies,proto3" json:"movies,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -308,16 +454,17 @@ func (m *MovieCatalog) Reset()         { *m = MovieCatalog{} }
 func (m *MovieCatalog) String() string { return proto.CompactTextString(m) }
 func (*MovieCatalog) ProtoMessage()    {}
 func (*MovieCatalog) Descriptor() ([]byte, []int) {
-	return fileDescriptor_moviecat_223631d182a39b34, []int{3}
+	return fileDescriptor_651fdac2fff37738, []int{3}
 }
+
 func (m *MovieCatalog) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MovieCatalog.Unmarshal(m, b)
 }
 func (m *MovieCatalog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_MovieCatalog.Marshal(b, m, deterministic)
 }
-func (dst *MovieCatalog) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_MovieCatalog.Merge(dst, src)
+func (m *MovieCatalog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MovieCatalog.Merge(m, src)
 }
 func (m *MovieCatalog) XXX_Size() int {
 	return xxx_messageInfo_MovieCatalog.Size(m)
@@ -370,46 +517,937 @@ func (m *MovieCatalog) GetMovies() []*MovieEntry {
 	return nil
 }
 
+type CatalogInfo struct {
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PublishedAt          int64    `protobuf:"varint,2,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	Md5Hash              []byte   `protobuf:"bytes,3,opt,name=md5_hash,json=md5Hash,proto3" json:"md5_hash,omitempty"`
+	ChannelsCount        int64    `protobuf:"varint,4,opt,name=channels_count,json=channelsCount,proto3" json:"channels_count,omitempty"`
+	TopicsCount          int64    `protobuf:"varint,5,opt,name=topics_count,json=topicsCount,proto3" json:"topics_count,omitempty"`
+	MoviesCount          int64    `protobuf:"varint,6,opt,name=movies_count,json=moviesCount,proto3" json:"movies_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CatalogInfo) Reset()         { *m = CatalogInfo{} }
+func (m *CatalogInfo) String() string { return proto.CompactTextString(m) }
+func (*CatalogInfo) ProtoMessage()    {}
+func (*CatalogInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{4}
+}
+
+func (m *CatalogInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CatalogInfo.Unmarshal(m, b)
+}
+func (m *CatalogInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CatalogInfo.Marshal(b, m, deterministic)
+}
+func (m *CatalogInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CatalogInfo.Merge(m, src)
+}
+func (m *CatalogInfo) XXX_Size() int {
+	return xxx_messageInfo_CatalogInfo.Size(m)
+}
+func (m *CatalogInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_CatalogInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CatalogInfo proto.InternalMessageInfo
+
+func (m *CatalogInfo) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *CatalogInfo) GetPublishedAt() int64 {
+	if m != nil {
+		return m.PublishedAt
+	}
+	return 0
+}
+
+func (m *CatalogInfo) GetMd5Hash() []byte {
+	if m != nil {
+		return m.Md5Hash
+	}
+	return nil
+}
+
+func (m *CatalogInfo) GetChannelsCount() int64 {
+	if m != nil {
+		return m.ChannelsCount
+	}
+	return 0
+}
+
+func (m *CatalogInfo) GetTopicsCount() int64 {
+	if m != nil {
+		return m.TopicsCount
+	}
+	return 0
+}
+
+func (m *CatalogInfo) GetMoviesCount() int64 {
+	if m != nil {
+		return m.MoviesCount
+	}
+	return 0
+}
+
+type MovieCatalogChunk struct {
+	Info                 *CatalogInfo    `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Channels             []*ChannelEntry `protobuf:"bytes,2,rep,name=channels,proto3" json:"channels,omitempty"`
+	Topics               []*TopicEntry   `protobuf:"bytes,3,rep,name=topics,proto3" json:"topics,omitempty"`
+	Movies               []*MovieEntry   `protobuf:"bytes,4,rep,name=movies,proto3" json:"movies,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MovieCatalogChunk) Reset()         { *m = MovieCatalogChunk{} }
+func (m *MovieCatalogChunk) String() string { return proto.CompactTextString(m) }
+func (*MovieCatalogChunk) ProtoMessage()    {}
+func (*MovieCatalogChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{5}
+}
+
+func (m *MovieCatalogChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MovieCatalogChunk.Unmarshal(m, b)
+}
+func (m *MovieCatalogChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MovieCatalogChunk.Marshal(b, m, deterministic)
+}
+func (m *MovieCatalogChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MovieCatalogChunk.Merge(m, src)
+}
+func (m *MovieCatalogChunk) XXX_Size() int {
+	return xxx_messageInfo_MovieCatalogChunk.Size(m)
+}
+func (m *MovieCatalogChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_MovieCatalogChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MovieCatalogChunk proto.InternalMessageInfo
+
+func (m *MovieCatalogChunk) GetInfo() *CatalogInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *MovieCatalogChunk) GetChannels() []*ChannelEntry {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *MovieCatalogChunk) GetTopics() []*TopicEntry {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *MovieCatalogChunk) GetMovies() []*MovieEntry {
+	if m != nil {
+		return m.Movies
+	}
+	return nil
+}
+
+type GetCatalogInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCatalogInfoRequest) Reset()         { *m = GetCatalogInfoRequest{} }
+func (m *GetCatalogInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCatalogInfoRequest) ProtoMessage()    {}
+func (*GetCatalogInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{6}
+}
+
+func (m *GetCatalogInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCatalogInfoRequest.Unmarshal(m, b)
+}
+func (m *GetCatalogInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCatalogInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *GetCatalogInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCatalogInfoRequest.Merge(m, src)
+}
+func (m *GetCatalogInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCatalogInfoRequest.Size(m)
+}
+func (m *GetCatalogInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCatalogInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCatalogInfoRequest proto.InternalMessageInfo
+
+type ListChannelsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChannelsRequest) Reset()         { *m = ListChannelsRequest{} }
+func (m *ListChannelsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListChannelsRequest) ProtoMessage()    {}
+func (*ListChannelsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{7}
+}
+
+func (m *ListChannelsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChannelsRequest.Unmarshal(m, b)
+}
+func (m *ListChannelsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChannelsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListChannelsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChannelsRequest.Merge(m, src)
+}
+func (m *ListChannelsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListChannelsRequest.Size(m)
+}
+func (m *ListChannelsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChannelsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChannelsRequest proto.InternalMessageInfo
+
+type ListChannelsResponse struct {
+	Channels             []*ChannelEntry `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListChannelsResponse) Reset()         { *m = ListChannelsResponse{} }
+func (m *ListChannelsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListChannelsResponse) ProtoMessage()    {}
+func (*ListChannelsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{8}
+}
+
+func (m *ListChannelsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChannelsResponse.Unmarshal(m, b)
+}
+func (m *ListChannelsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChannelsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListChannelsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChannelsResponse.Merge(m, src)
+}
+func (m *ListChannelsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListChannelsResponse.Size(m)
+}
+func (m *ListChannelsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChannelsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChannelsResponse proto.InternalMessageInfo
+
+func (m *ListChannelsResponse) GetChannels() []*ChannelEntry {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type ListTopicsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTopicsRequest) Reset()         { *m = ListTopicsRequest{} }
+func (m *ListTopicsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTopicsRequest) ProtoMessage()    {}
+func (*ListTopicsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{9}
+}
+
+func (m *ListTopicsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTopicsRequest.Unmarshal(m, b)
+}
+func (m *ListTopicsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTopicsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTopicsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTopicsRequest.Merge(m, src)
+}
+func (m *ListTopicsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTopicsRequest.Size(m)
+}
+func (m *ListTopicsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTopicsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTopicsRequest proto.InternalMessageInfo
+
+type ListTopicsResponse struct {
+	Topics               []*TopicEntry `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListTopicsResponse) Reset()         { *m = ListTopicsResponse{} }
+func (m *ListTopicsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTopicsResponse) ProtoMessage()    {}
+func (*ListTopicsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{10}
+}
+
+func (m *ListTopicsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTopicsResponse.Unmarshal(m, b)
+}
+func (m *ListTopicsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTopicsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTopicsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTopicsResponse.Merge(m, src)
+}
+func (m *ListTopicsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTopicsResponse.Size(m)
+}
+func (m *ListTopicsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTopicsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTopicsResponse proto.InternalMessageInfo
+
+func (m *ListTopicsResponse) GetTopics() []*TopicEntry {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+type SearchMoviesRequest struct {
+	Query                string   `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	ChannelId            int64    `protobuf:"varint,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	TopicId              int64    `protobuf:"varint,3,opt,name=topic_id,json=topicId,proto3" json:"topic_id,omitempty"`
+	Limit                int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32    `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchMoviesRequest) Reset()         { *m = SearchMoviesRequest{} }
+func (m *SearchMoviesRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchMoviesRequest) ProtoMessage()    {}
+func (*SearchMoviesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{11}
+}
+
+func (m *SearchMoviesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchMoviesRequest.Unmarshal(m, b)
+}
+func (m *SearchMoviesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchMoviesRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchMoviesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchMoviesRequest.Merge(m, src)
+}
+func (m *SearchMoviesRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchMoviesRequest.Size(m)
+}
+func (m *SearchMoviesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchMoviesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchMoviesRequest proto.InternalMessageInfo
+
+func (m *SearchMoviesRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *SearchMoviesRequest) GetChannelId() int64 {
+	if m != nil {
+		return m.ChannelId
+	}
+	return 0
+}
+
+func (m *SearchMoviesRequest) GetTopicId() int64 {
+	if m != nil {
+		return m.TopicId
+	}
+	return 0
+}
+
+func (m *SearchMoviesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *SearchMoviesRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type StreamCatalogRequest struct {
+	ChunkSize            int32    `protobuf:"varint,1,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamCatalogRequest) Reset()         { *m = StreamCatalogRequest{} }
+func (m *StreamCatalogRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamCatalogRequest) ProtoMessage()    {}
+func (*StreamCatalogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{12}
+}
+
+func (m *StreamCatalogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamCatalogRequest.Unmarshal(m, b)
+}
+func (m *StreamCatalogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamCatalogRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamCatalogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamCatalogRequest.Merge(m, src)
+}
+func (m *StreamCatalogRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamCatalogRequest.Size(m)
+}
+func (m *StreamCatalogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamCatalogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamCatalogRequest proto.InternalMessageInfo
+
+func (m *StreamCatalogRequest) GetChunkSize() int32 {
+	if m != nil {
+		return m.ChunkSize
+	}
+	return 0
+}
+
+type MovieCatalogHeader struct {
+	Version     int32           `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PublishedAt int64           `protobuf:"varint,2,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	Md5Hash     []byte          `protobuf:"bytes,3,opt,name=md5_hash,json=md5Hash,proto3" json:"md5_hash,omitempty"`
+	Channels    []*ChannelEntry `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+	Topics      []*TopicEntry   `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty"`
+	MoviesCount int64           `protobuf:"varint,6,opt,name=movies_count,json=moviesCount,proto3" json:"movies_count,omitempty"`
+	ListVersion int32           `protobuf:"varint,7,opt,name=list_version,json=listVersion,proto3" json:"list_version,omitempty"`
+	// If set, the subtitle, small format, HD format and history URLs of the
+	// movie entries are relative to their URL. See CompactURL.
+	CompactUrls          bool     `protobuf:"varint,8,opt,name=compact_urls,json=compactUrls,proto3" json:"compact_urls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MovieCatalogHeader) Reset()         { *m = MovieCatalogHeader{} }
+func (m *MovieCatalogHeader) String() string { return proto.CompactTextString(m) }
+func (*MovieCatalogHeader) ProtoMessage()    {}
+func (*MovieCatalogHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{13}
+}
+
+func (m *MovieCatalogHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MovieCatalogHeader.Unmarshal(m, b)
+}
+func (m *MovieCatalogHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MovieCatalogHeader.Marshal(b, m, deterministic)
+}
+func (m *MovieCatalogHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MovieCatalogHeader.Merge(m, src)
+}
+func (m *MovieCatalogHeader) XXX_Size() int {
+	return xxx_messageInfo_MovieCatalogHeader.Size(m)
+}
+func (m *MovieCatalogHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_MovieCatalogHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MovieCatalogHeader proto.InternalMessageInfo
+
+func (m *MovieCatalogHeader) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *MovieCatalogHeader) GetPublishedAt() int64 {
+	if m != nil {
+		return m.PublishedAt
+	}
+	return 0
+}
+
+func (m *MovieCatalogHeader) GetMd5Hash() []byte {
+	if m != nil {
+		return m.Md5Hash
+	}
+	return nil
+}
+
+func (m *MovieCatalogHeader) GetChannels() []*ChannelEntry {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *MovieCatalogHeader) GetTopics() []*TopicEntry {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *MovieCatalogHeader) GetMoviesCount() int64 {
+	if m != nil {
+		return m.MoviesCount
+	}
+	return 0
+}
+
+func (m *MovieCatalogHeader) GetListVersion() int32 {
+	if m != nil {
+		return m.ListVersion
+	}
+	return 0
+}
+
+func (m *MovieCatalogHeader) GetCompactUrls() bool {
+	if m != nil {
+		return m.CompactUrls
+	}
+	return false
+}
+
+type MovieEntryChunk struct {
+	Movies               []*MovieEntry `protobuf:"bytes,1,rep,name=movies,proto3" json:"movies,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *MovieEntryChunk) Reset()         { *m = MovieEntryChunk{} }
+func (m *MovieEntryChunk) String() string { return proto.CompactTextString(m) }
+func (*MovieEntryChunk) ProtoMessage()    {}
+func (*MovieEntryChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_651fdac2fff37738, []int{14}
+}
+
+func (m *MovieEntryChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MovieEntryChunk.Unmarshal(m, b)
+}
+func (m *MovieEntryChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MovieEntryChunk.Marshal(b, m, deterministic)
+}
+func (m *MovieEntryChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MovieEntryChunk.Merge(m, src)
+}
+func (m *MovieEntryChunk) XXX_Size() int {
+	return xxx_messageInfo_MovieEntryChunk.Size(m)
+}
+func (m *MovieEntryChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_MovieEntryChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MovieEntryChunk proto.InternalMessageInfo
+
+func (m *MovieEntryChunk) GetMovies() []*MovieEntry {
+	if m != nil {
+		return m.Movies
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("moviecat.GeoRegion", GeoRegion_name, GeoRegion_value)
 	proto.RegisterType((*ChannelEntry)(nil), "moviecat.ChannelEntry")
 	proto.RegisterType((*TopicEntry)(nil), "moviecat.TopicEntry")
 	proto.RegisterType((*MovieEntry)(nil), "moviecat.MovieEntry")
 	proto.RegisterType((*MovieCatalog)(nil), "moviecat.MovieCatalog")
+	proto.RegisterType((*CatalogInfo)(nil), "moviecat.CatalogInfo")
+	proto.RegisterType((*MovieCatalogChunk)(nil), "moviecat.MovieCatalogChunk")
+	proto.RegisterType((*GetCatalogInfoRequest)(nil), "moviecat.GetCatalogInfoRequest")
+	proto.RegisterType((*ListChannelsRequest)(nil), "moviecat.ListChannelsRequest")
+	proto.RegisterType((*ListChannelsResponse)(nil), "moviecat.ListChannelsResponse")
+	proto.RegisterType((*ListTopicsRequest)(nil), "moviecat.ListTopicsRequest")
+	proto.RegisterType((*ListTopicsResponse)(nil), "moviecat.ListTopicsResponse")
+	proto.RegisterType((*SearchMoviesRequest)(nil), "moviecat.SearchMoviesRequest")
+	proto.RegisterType((*StreamCatalogRequest)(nil), "moviecat.StreamCatalogRequest")
+	proto.RegisterType((*MovieCatalogHeader)(nil), "moviecat.MovieCatalogHeader")
+	proto.RegisterType((*MovieEntryChunk)(nil), "moviecat.MovieEntryChunk")
+}
+
+func init() { proto.RegisterFile("moviecat.proto", fileDescriptor_651fdac2fff37738) }
+
+var fileDescriptor_651fdac2fff37738 = []byte{
+	// 1150 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x57, 0xdd, 0x72, 0xdb, 0x44,
+	0x14, 0x46, 0xfe, 0xf7, 0xf1, 0x4f, 0xe4, 0xb5, 0x93, 0xaa, 0x4e, 0x43, 0x5c, 0x31, 0x30, 0x0e,
+	0x30, 0xa5, 0x13, 0xc8, 0x35, 0x53, 0x1c, 0x37, 0x76, 0x27, 0x4d, 0x18, 0xb9, 0x69, 0x2f, 0x35,
+	0x6b, 0x6b, 0x63, 0xed, 0x54, 0x96, 0x5c, 0xad, 0x94, 0x90, 0xbe, 0x01, 0x37, 0xdc, 0xf0, 0x30,
+	0x0c, 0x2f, 0xc1, 0x05, 0x0f, 0xc3, 0x35, 0xb3, 0x47, 0xb2, 0x25, 0x3b, 0xa6, 0x49, 0x67, 0xe0,
+	0x82, 0x3b, 0x9f, 0xef, 0x7c, 0x7b, 0xf6, 0x7c, 0xda, 0xfd, 0xce, 0x26, 0x50, 0x9f, 0x79, 0x57,
+	0x9c, 0x4d, 0x68, 0xf0, 0x64, 0xee, 0x7b, 0x81, 0x47, 0x4a, 0x8b, 0x58, 0x3f, 0x85, 0x6a, 0xcf,
+	0xa6, 0xae, 0xcb, 0x9c, 0xbe, 0x1b, 0xf8, 0x37, 0x44, 0x83, 0xe2, 0x15, 0xf3, 0x05, 0xf7, 0x5c,
+	0x4d, 0xe9, 0x28, 0xdd, 0xbc, 0xb1, 0x08, 0x49, 0x1d, 0x32, 0xdc, 0xd2, 0x32, 0x1d, 0xa5, 0x9b,
+	0x35, 0x32, 0xdc, 0x22, 0x04, 0x72, 0x2e, 0x9d, 0x31, 0x2d, 0xdb, 0x51, 0xba, 0x65, 0x03, 0x7f,
+	0xeb, 0x2f, 0x00, 0x5e, 0x79, 0x73, 0x3e, 0xf9, 0x37, 0x6a, 0xfd, 0x5e, 0x04, 0x78, 0x29, 0xdb,
+	0xbc, 0x7f, 0xb1, 0x32, 0x16, 0xdb, 0x03, 0x98, 0x44, 0x92, 0x4c, 0x6e, 0x61, 0xc9, 0xac, 0x51,
+	0x8e, 0x91, 0xa1, 0x45, 0x1e, 0x42, 0x29, 0x90, 0x3d, 0xca, 0x64, 0x0e, 0x93, 0x45, 0x8c, 0x87,
+	0x16, 0x69, 0x41, 0x3e, 0xe0, 0x81, 0xc3, 0xb4, 0x3c, 0x16, 0x8b, 0x02, 0xf2, 0x18, 0xaa, 0xf3,
+	0x70, 0xec, 0x70, 0x61, 0x33, 0xcb, 0xa4, 0x81, 0x56, 0xc0, 0x45, 0x95, 0x25, 0xf6, 0x2c, 0x20,
+	0x6d, 0x28, 0x59, 0xa1, 0x4f, 0x03, 0xd9, 0x5d, 0x11, 0xd3, 0xcb, 0x58, 0x6a, 0x13, 0xfc, 0x3d,
+	0xd3, 0x4a, 0x88, 0xe3, 0x6f, 0xb9, 0x91, 0xc5, 0xc4, 0xc4, 0xd7, 0xca, 0xd1, 0x46, 0x18, 0x10,
+	0x15, 0xb2, 0xa1, 0xef, 0x68, 0x80, 0x98, 0xfc, 0x49, 0xbe, 0x80, 0x2d, 0x9b, 0x0a, 0xf3, 0x9a,
+	0x8d, 0x05, 0x0f, 0x98, 0x29, 0xb3, 0x95, 0x8e, 0xd2, 0x2d, 0x19, 0x35, 0x9b, 0x8a, 0x37, 0x11,
+	0x7a, 0xe1, 0x3b, 0xa4, 0x0b, 0xaa, 0xe4, 0x89, 0x70, 0x8c, 0x2d, 0x23, 0xb1, 0x8a, 0xc4, 0xba,
+	0x4d, 0xc5, 0x28, 0x86, 0x25, 0xf3, 0x1b, 0x68, 0x21, 0x73, 0x46, 0x1d, 0xc7, 0xbc, 0xf4, 0xfc,
+	0x19, 0x0d, 0x90, 0x5d, 0x43, 0x76, 0x43, 0xb2, 0x65, 0xea, 0x39, 0x66, 0xe4, 0x82, 0x03, 0x90,
+	0xa0, 0x69, 0x5b, 0x69, 0x76, 0x7d, 0x59, 0x7b, 0x60, 0x25, 0xd4, 0xb8, 0x5b, 0x9b, 0x8b, 0xc0,
+	0xf3, 0x6f, 0x90, 0xb8, 0xb5, 0xec, 0x76, 0x10, 0xa1, 0x92, 0xa7, 0x42, 0x76, 0xca, 0x3c, 0x4d,
+	0x8d, 0x74, 0x4e, 0x99, 0x47, 0xb6, 0xa1, 0xc0, 0x85, 0xe9, 0xb2, 0x6b, 0xad, 0x81, 0x0b, 0xf2,
+	0x5c, 0x9c, 0xb1, 0x6b, 0xb2, 0x0f, 0x95, 0xb4, 0x74, 0x82, 0x0b, 0xe0, 0x3a, 0xd1, 0xfd, 0x18,
+	0xaa, 0x2b, 0x9a, 0x9b, 0xc8, 0xa8, 0x88, 0x94, 0xe0, 0x2e, 0xa8, 0xb7, 0xc4, 0xb6, 0x90, 0x56,
+	0x17, 0xab, 0x4a, 0x75, 0xa8, 0xad, 0xaa, 0xdc, 0x8e, 0xaa, 0xd9, 0x29, 0x89, 0xfb, 0x50, 0x49,
+	0xcb, 0xdb, 0x89, 0x3a, 0xb2, 0x13, 0x6d, 0xbb, 0x50, 0x0e, 0x5d, 0xfe, 0x93, 0x69, 0xd1, 0x80,
+	0x69, 0x0f, 0xa2, 0xab, 0x20, 0x81, 0x63, 0x1a, 0x30, 0xf2, 0x15, 0x34, 0x68, 0x68, 0x71, 0xcf,
+	0xc4, 0xf3, 0xe6, 0x73, 0xbc, 0x2f, 0x1a, 0x2a, 0x56, 0x31, 0x71, 0x9c, 0xe0, 0xe4, 0x33, 0xa8,
+	0x09, 0x3e, 0x75, 0x4d, 0x87, 0xba, 0xd3, 0x90, 0x4e, 0x99, 0xf6, 0x10, 0x89, 0x55, 0x09, 0x9e,
+	0xc6, 0x18, 0xf9, 0x0e, 0x2a, 0x53, 0xe6, 0x99, 0x3e, 0x9b, 0x72, 0xcf, 0x15, 0x5a, 0xbb, 0x93,
+	0xed, 0xd6, 0x0f, 0x9b, 0x4f, 0x96, 0x76, 0x3f, 0x61, 0x9e, 0x81, 0x39, 0x03, 0xa6, 0x8b, 0x9f,
+	0x82, 0x1c, 0x80, 0xea, 0xf9, 0x7c, 0xca, 0x5d, 0xea, 0x98, 0x0b, 0x53, 0xed, 0x62, 0xf5, 0xad,
+	0x05, 0xfe, 0x3a, 0x82, 0xc9, 0x03, 0x28, 0x8e, 0xa9, 0x60, 0xd2, 0x2c, 0x8f, 0x50, 0x6c, 0x41,
+	0x86, 0x43, 0x4b, 0x0a, 0x15, 0x01, 0x1d, 0x3b, 0x98, 0xda, 0xc3, 0x54, 0x29, 0x02, 0x86, 0x96,
+	0xfe, 0x97, 0x02, 0x55, 0xf4, 0x6e, 0x8f, 0x06, 0xd4, 0xf1, 0xa6, 0x1f, 0x70, 0xef, 0xba, 0xbb,
+	0x32, 0xb7, 0xdd, 0xf5, 0x10, 0x4a, 0x33, 0xeb, 0xc8, 0xb4, 0xa9, 0xb0, 0xd1, 0xce, 0x55, 0xa3,
+	0x38, 0xb3, 0x8e, 0x06, 0x54, 0xd8, 0xe4, 0x10, 0x4a, 0xb1, 0xb3, 0x85, 0x96, 0xeb, 0x64, 0xbb,
+	0x95, 0xc3, 0x9d, 0x44, 0x7c, 0x7a, 0xb0, 0x19, 0x4b, 0x1e, 0xf9, 0x1a, 0x0a, 0x68, 0x78, 0xa1,
+	0xe5, 0x71, 0x45, 0x2b, 0x59, 0x91, 0x0c, 0x2f, 0x23, 0xe6, 0x48, 0x36, 0xa6, 0x85, 0x56, 0x58,
+	0x67, 0x27, 0xd3, 0xc9, 0x88, 0x39, 0xfa, 0x9f, 0x0a, 0x54, 0x62, 0xcd, 0x43, 0xf7, 0xd2, 0xfb,
+	0xcf, 0x74, 0x7f, 0x0e, 0xf5, 0x85, 0x1e, 0x73, 0xe2, 0x85, 0x6e, 0x10, 0x8f, 0xb2, 0xda, 0x02,
+	0xed, 0x49, 0x50, 0x6e, 0x12, 0xc9, 0x88, 0x49, 0xf9, 0x68, 0x93, 0x08, 0x5b, 0x52, 0xa2, 0xde,
+	0x63, 0x4a, 0x3c, 0xdd, 0x22, 0x0c, 0x29, 0xfa, 0x1f, 0x0a, 0x34, 0xd2, 0xa7, 0xd9, 0xb3, 0x43,
+	0xf7, 0x2d, 0x39, 0x80, 0x1c, 0x77, 0x2f, 0x3d, 0xd4, 0x55, 0x39, 0xdc, 0x4e, 0x7d, 0xf6, 0x44,
+	0xbf, 0x81, 0x94, 0x95, 0x53, 0xca, 0x7c, 0xf4, 0x29, 0x65, 0x3f, 0xea, 0x94, 0x72, 0xf7, 0x38,
+	0xa5, 0x07, 0xb0, 0x7d, 0xc2, 0x82, 0x74, 0x9f, 0xec, 0x5d, 0xc8, 0x44, 0xa0, 0x6f, 0x43, 0xf3,
+	0x94, 0x8b, 0x20, 0x6e, 0x49, 0x2c, 0xe0, 0x17, 0xd0, 0x5a, 0x85, 0xc5, 0xdc, 0x73, 0x05, 0x5b,
+	0xd1, 0xa5, 0xdc, 0x4f, 0x97, 0xde, 0x84, 0x86, 0xac, 0x85, 0x1a, 0x96, 0x1b, 0xfc, 0x00, 0x24,
+	0x0d, 0xc6, 0xe5, 0x93, 0x4f, 0xa0, 0xdc, 0xfd, 0x09, 0xf4, 0x5f, 0x15, 0x68, 0x8e, 0x18, 0xf5,
+	0x27, 0x36, 0x2a, 0x5e, 0xd4, 0x96, 0x6f, 0xcd, 0xbb, 0x90, 0xf9, 0x37, 0x78, 0x50, 0x65, 0x23,
+	0x0a, 0xd6, 0x1e, 0xc9, 0xcc, 0x87, 0x1e, 0xc9, 0xec, 0xad, 0x47, 0xd2, 0xe1, 0x33, 0x1e, 0xdd,
+	0xb8, 0xbc, 0x11, 0x05, 0x64, 0x07, 0x0a, 0xde, 0xe5, 0xa5, 0x60, 0xd1, 0x1d, 0xcb, 0x1b, 0x71,
+	0xa4, 0x1f, 0x41, 0x6b, 0x14, 0xf8, 0x8c, 0xce, 0xe2, 0xaf, 0xbd, 0xe8, 0x0a, 0xf7, 0x0f, 0xdd,
+	0xb7, 0x26, 0xbe, 0x8d, 0x91, 0x37, 0xca, 0x88, 0x8c, 0xf8, 0x7b, 0xa6, 0xff, 0x96, 0x01, 0x92,
+	0xbe, 0x72, 0x03, 0x46, 0x2d, 0xe6, 0xff, 0x8f, 0xc7, 0xc8, 0xdd, 0x36, 0x93, 0x14, 0x87, 0x8b,
+	0x60, 0x39, 0x91, 0x8b, 0xa8, 0xb0, 0x22, 0xb1, 0xd7, 0x89, 0xca, 0x89, 0x37, 0x9b, 0xd3, 0x09,
+	0x3e, 0x50, 0x02, 0xff, 0xa6, 0x28, 0x19, 0x95, 0x18, 0xbb, 0xf0, 0x1d, 0xa1, 0x7f, 0x0f, 0x5b,
+	0xc9, 0x8d, 0x8f, 0x9c, 0x9a, 0x98, 0x43, 0xb9, 0xdb, 0x1c, 0x5f, 0xfe, 0xac, 0x40, 0x79, 0xf9,
+	0x6c, 0x90, 0x36, 0xec, 0x9c, 0xf4, 0xcf, 0x4d, 0xa3, 0x7f, 0x32, 0x3c, 0x3f, 0x33, 0x2f, 0xce,
+	0x46, 0x3f, 0xf6, 0x7b, 0xc3, 0xe7, 0xc3, 0xfe, 0xb1, 0xfa, 0x09, 0x69, 0x40, 0x2d, 0x95, 0x3b,
+	0xee, 0xab, 0xca, 0x1a, 0xf4, 0xec, 0x95, 0x9a, 0x59, 0x83, 0x7a, 0x03, 0x35, 0xbb, 0x06, 0xf5,
+	0x2f, 0xd4, 0x1c, 0x69, 0x81, 0x9a, 0x82, 0xde, 0x9c, 0x1b, 0xa7, 0xc7, 0x6a, 0xfe, 0xf0, 0x97,
+	0x2c, 0x34, 0xd3, 0xd7, 0x60, 0xc4, 0xfc, 0x2b, 0x3e, 0x61, 0x64, 0x00, 0xf5, 0x55, 0x03, 0x93,
+	0xfd, 0xf4, 0x9b, 0xb7, 0xc1, 0xda, 0xed, 0xcd, 0x03, 0x8a, 0xbc, 0x84, 0x6a, 0xda, 0xda, 0x64,
+	0x2f, 0xa1, 0x6d, 0x98, 0x04, 0xed, 0x4f, 0xff, 0x29, 0x1d, 0x5b, 0xf6, 0x04, 0x20, 0x31, 0x32,
+	0xd9, 0x5d, 0x65, 0xaf, 0x78, 0xbe, 0xfd, 0x68, 0x73, 0x32, 0x2e, 0xd4, 0x87, 0x6a, 0xda, 0xcc,
+	0xe9, 0xbe, 0x36, 0x98, 0xbc, 0xbd, 0xf1, 0x48, 0x9f, 0x2a, 0xe4, 0x0c, 0x6a, 0x2b, 0xf6, 0x23,
+	0x29, 0x01, 0x9b, 0x7c, 0xd9, 0xde, 0x5d, 0x2b, 0x94, 0x1e, 0xf9, 0x4f, 0x95, 0x71, 0x01, 0xff,
+	0x7f, 0xf8, 0xf6, 0xef, 0x01, 0x00, 0x78, 0xfa, 0x27, 0x63, 0x51, 0x0c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// MovieCatalogServiceClient is the client API for MovieCatalogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MovieCatalogServiceClient interface {
+	GetCatalogInfo(ctx context.Context, in *GetCatalogInfoRequest, opts ...grpc.CallOption) (*CatalogInfo, error)
+	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	ListTopics(ctx context.Context, in *ListTopicsRequest, opts ...grpc.CallOption) (*ListTopicsResponse, error)
+	SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (MovieCatalogService_SearchMoviesClient, error)
+	StreamCatalog(ctx context.Context, in *StreamCatalogRequest, opts ...grpc.CallOption) (MovieCatalogService_StreamCatalogClient, error)
+}
+
+type movieCatalogServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMovieCatalogServiceClient(cc grpc.ClientConnInterface) MovieCatalogServiceClient {
+	return &movieCatalogServiceClient{cc}
+}
+
+func (c *movieCatalogServiceClient) GetCatalogInfo(ctx context.Context, in *GetCatalogInfoRequest, opts ...grpc.CallOption) (*CatalogInfo, error) {
+	out := new(CatalogInfo)
+	err := c.cc.Invoke(ctx, "/moviecat.MovieCatalogService/GetCatalogInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieCatalogServiceClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
+	out := new(ListChannelsResponse)
+	err := c.cc.Invoke(ctx, "/moviecat.MovieCatalogService/ListChannels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieCatalogServiceClient) ListTopics(ctx context.Context, in *ListTopicsRequest, opts ...grpc.CallOption) (*ListTopicsResponse, error) {
+	out := new(ListTopicsResponse)
+	err := c.cc.Invoke(ctx, "/moviecat.MovieCatalogService/ListTopics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieCatalogServiceClient) SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (MovieCatalogService_SearchMoviesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MovieCatalogService_serviceDesc.Streams[0], "/moviecat.MovieCatalogService/SearchMovies", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &movieCatalogServiceSearchMoviesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MovieCatalogService_SearchMoviesClient interface {
+	Recv() (*MovieEntry, error)
+	grpc.ClientStream
+}
+
+type movieCatalogServiceSearchMoviesClient struct {
+	grpc.ClientStream
+}
+
+func (x *movieCatalogServiceSearchMoviesClient) Recv() (*MovieEntry, error) {
+	m := new(MovieEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *movieCatalogServiceClient) StreamCatalog(ctx context.Context, in *StreamCatalogRequest, opts ...grpc.CallOption) (MovieCatalogService_StreamCatalogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MovieCatalogService_serviceDesc.Streams[1], "/moviecat.MovieCatalogService/StreamCatalog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &movieCatalogServiceStreamCatalogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MovieCatalogService_StreamCatalogClient interface {
+	Recv() (*MovieCatalogChunk, error)
+	grpc.ClientStream
+}
+
+type movieCatalogServiceStreamCatalogClient struct {
+	grpc.ClientStream
+}
+
+func (x *movieCatalogServiceStreamCatalogClient) Recv() (*MovieCatalogChunk, error) {
+	m := new(MovieCatalogChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MovieCatalogServiceServer is the server API for MovieCatalogService service.
+type MovieCatalogServiceServer interface {
+	GetCatalogInfo(context.Context, *GetCatalogInfoRequest) (*CatalogInfo, error)
+	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
+	ListTopics(context.Context, *ListTopicsRequest) (*ListTopicsResponse, error)
+	SearchMovies(*SearchMoviesRequest, MovieCatalogService_SearchMoviesServer) error
+	StreamCatalog(*StreamCatalogRequest, MovieCatalogService_StreamCatalogServer) error
+}
+
+// UnimplementedMovieCatalogServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMovieCatalogServiceServer struct {
+}
+
+func (*UnimplementedMovieCatalogServiceServer) GetCatalogInfo(ctx context.Context, req *GetCatalogInfoRequest) (*CatalogInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCatalogInfo not implemented")
+}
+func (*UnimplementedMovieCatalogServiceServer) ListChannels(ctx context.Context, req *ListChannelsRequest) (*ListChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChannels not implemented")
+}
+func (*UnimplementedMovieCatalogServiceServer) ListTopics(ctx context.Context, req *ListTopicsRequest) (*ListTopicsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTopics not implemented")
+}
+func (*UnimplementedMovieCatalogServiceServer) SearchMovies(req *SearchMoviesRequest, srv MovieCatalogService_SearchMoviesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchMovies not implemented")
+}
+func (*UnimplementedMovieCatalogServiceServer) StreamCatalog(req *StreamCatalogRequest, srv MovieCatalogService_StreamCatalogServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCatalog not implemented")
+}
+
+func RegisterMovieCatalogServiceServer(s *grpc.Server, srv MovieCatalogServiceServer) {
+	s.RegisterService(&_MovieCatalogService_serviceDesc, srv)
+}
+
+func _MovieCatalogService_GetCatalogInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatalogInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieCatalogServiceServer).GetCatalogInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moviecat.MovieCatalogService/GetCatalogInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieCatalogServiceServer).GetCatalogInfo(ctx, req.(*GetCatalogInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieCatalogService_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieCatalogServiceServer).ListChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moviecat.MovieCatalogService/ListChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieCatalogServiceServer).ListChannels(ctx, req.(*ListChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieCatalogService_ListTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTopicsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieCatalogServiceServer).ListTopics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moviecat.MovieCatalogService/ListTopics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieCatalogServiceServer).ListTopics(ctx, req.(*ListTopicsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieCatalogService_SearchMovies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchMoviesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MovieCatalogServiceServer).SearchMovies(m, &movieCatalogServiceSearchMoviesServer{stream})
+}
+
+type MovieCatalogService_SearchMoviesServer interface {
+	Send(*MovieEntry) error
+	grpc.ServerStream
+}
+
+type movieCatalogServiceSearchMoviesServer struct {
+	grpc.ServerStream
+}
+
+func (x *movieCatalogServiceSearchMoviesServer) Send(m *MovieEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MovieCatalogService_StreamCatalog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCatalogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MovieCatalogServiceServer).StreamCatalog(m, &movieCatalogServiceStreamCatalogServer{stream})
+}
+
+type MovieCatalogService_StreamCatalogServer interface {
+	Send(*MovieCatalogChunk) error
+	grpc.ServerStream
+}
+
+type movieCatalogServiceStreamCatalogServer struct {
+	grpc.ServerStream
+}
+
+func (x *movieCatalogServiceStreamCatalogServer) Send(m *MovieCatalogChunk) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func init() { proto.RegisterFile("moviecat.proto", fileDescriptor_moviecat_223631d182a39b34) }
-
-var fileDescriptor_moviecat_223631d182a39b34 = []byte{
-	// 482 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x93, 0x51, 0x8b, 0xd3, 0x40,
-	0x10, 0xc7, 0x49, 0xd3, 0xa4, 0xe9, 0x34, 0xd7, 0xeb, 0x2d, 0x55, 0xf6, 0x04, 0x21, 0xf6, 0x41,
-	0x22, 0xc8, 0x09, 0x27, 0xf7, 0x01, 0xe4, 0x50, 0x7a, 0xa2, 0x3e, 0x44, 0xc5, 0xc7, 0xb0, 0xed,
-	0xae, 0xdd, 0x85, 0x24, 0x5b, 0x76, 0xb7, 0x57, 0xce, 0x27, 0x3f, 0xb5, 0xcf, 0xb2, 0x93, 0x5c,
-	0x7a, 0x54, 0x10, 0x1f, 0x7c, 0xdb, 0xf9, 0xff, 0x7f, 0x33, 0x3b, 0xcb, 0xce, 0xc0, 0xb4, 0xd6,
-	0xb7, 0x4a, 0xac, 0x99, 0xbb, 0xd8, 0x1a, 0xed, 0x34, 0x49, 0xee, 0xe3, 0xc5, 0x07, 0x48, 0xaf,
-	0x25, 0x6b, 0x1a, 0x51, 0xbd, 0x6d, 0x9c, 0xb9, 0x23, 0x14, 0x46, 0xb7, 0xc2, 0x58, 0xa5, 0x1b,
-	0x1a, 0x64, 0x41, 0x1e, 0x15, 0xf7, 0x21, 0x99, 0xc2, 0x40, 0x71, 0x3a, 0xc8, 0x82, 0x3c, 0x2c,
-	0x06, 0x8a, 0x13, 0x02, 0xc3, 0x86, 0xd5, 0x82, 0x86, 0x59, 0x90, 0x8f, 0x0b, 0x3c, 0x2f, 0xde,
-	0x03, 0x7c, 0xd1, 0x5b, 0xb5, 0xfe, 0x1f, 0xb5, 0x7e, 0x0e, 0x01, 0x3e, 0xfa, 0x36, 0xff, 0xbd,
-	0xd8, 0x18, 0x8b, 0x3d, 0x05, 0x58, 0xb7, 0x4f, 0x2a, 0x15, 0xc7, 0x92, 0x61, 0x31, 0xee, 0x94,
-	0x1b, 0x4e, 0xce, 0x21, 0x71, 0xbe, 0x47, 0x6f, 0x0e, 0xd1, 0x1c, 0x61, 0x7c, 0xc3, 0xc9, 0x1c,
-	0x22, 0xa7, 0x5c, 0x25, 0x68, 0x84, 0xc5, 0xda, 0x80, 0x3c, 0x83, 0x74, 0xbb, 0x5b, 0x55, 0xca,
-	0x4a, 0xc1, 0x4b, 0xe6, 0x68, 0x8c, 0x49, 0x93, 0x5e, 0x7b, 0xe3, 0xc8, 0x13, 0x48, 0xf8, 0xce,
-	0x30, 0xe7, 0xbb, 0x1b, 0xa1, 0xdd, 0xc7, 0xfe, 0x6d, 0x56, 0xfd, 0x10, 0x34, 0x41, 0x1d, 0xcf,
-	0xfe, 0x22, 0x2e, 0xec, 0xda, 0xd0, 0x71, 0x7b, 0x11, 0x06, 0x64, 0x06, 0xe1, 0xce, 0x54, 0x14,
-	0x50, 0xf3, 0x47, 0xf2, 0x1c, 0x4e, 0x25, 0xb3, 0xe5, 0x5e, 0xac, 0xac, 0x72, 0xa2, 0xf4, 0xee,
-	0x24, 0x0b, 0xf2, 0xa4, 0x38, 0x91, 0xcc, 0x7e, 0x6b, 0xd5, 0xaf, 0xa6, 0x22, 0x39, 0xcc, 0x3c,
-	0x67, 0x77, 0x2b, 0x6c, 0x19, 0xc1, 0x14, 0xc1, 0xa9, 0x64, 0xf6, 0x73, 0x27, 0x7b, 0xf2, 0x15,
-	0xcc, 0x91, 0xac, 0x59, 0x55, 0x95, 0xdf, 0xb5, 0xa9, 0x99, 0x43, 0xfa, 0x04, 0xe9, 0x33, 0x4f,
-	0x7b, 0xeb, 0x1d, 0x3a, 0x3e, 0xe1, 0x05, 0x78, 0xb1, 0x94, 0xfc, 0x21, 0x3d, 0xed, 0x6b, 0x2f,
-	0xf9, 0x01, 0xed, 0xba, 0x95, 0xca, 0x3a, 0x6d, 0xee, 0x10, 0x3c, 0xed, 0xbb, 0x5d, 0xb6, 0xaa,
-	0xe7, 0x66, 0x10, 0x6e, 0x84, 0xa6, 0xb3, 0xf6, 0x9d, 0x1b, 0xa1, 0xc9, 0x23, 0x88, 0x95, 0x2d,
-	0x1b, 0xb1, 0xa7, 0x67, 0x98, 0x10, 0x29, 0xfb, 0x49, 0xec, 0x17, 0xbf, 0x02, 0x48, 0x71, 0x04,
-	0xae, 0x99, 0x63, 0x95, 0xde, 0xfc, 0x65, 0x08, 0x8e, 0x3f, 0x69, 0xf0, 0xe7, 0x27, 0x9d, 0x43,
-	0x52, 0xf3, 0xab, 0x52, 0x32, 0x2b, 0x71, 0x2a, 0xd2, 0x62, 0x54, 0xf3, 0xab, 0x25, 0xb3, 0x92,
-	0x5c, 0x42, 0xd2, 0x0d, 0x88, 0xa5, 0xc3, 0x2c, 0xcc, 0x27, 0x97, 0x8f, 0x2f, 0xfa, 0x95, 0x79,
-	0xb8, 0x1f, 0x45, 0xcf, 0x91, 0x97, 0x10, 0xe3, 0xdc, 0x58, 0x1a, 0x61, 0xc6, 0xfc, 0x90, 0x71,
-	0xd8, 0x81, 0xa2, 0x63, 0x3c, 0x8d, 0xb6, 0xa5, 0xf1, 0x31, 0x7d, 0x18, 0xf2, 0xa2, 0x63, 0x56,
-	0x31, 0xae, 0xe9, 0xeb, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x6a, 0xdc, 0x58, 0x85, 0xb8, 0x03,
-	0x00, 0x00,
+var _MovieCatalogService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "moviecat.MovieCatalogService",
+	HandlerType: (*MovieCatalogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCatalogInfo",
+			Handler:    _MovieCatalogService_GetCatalogInfo_Handler,
+		},
+		{
+			MethodName: "ListChannels",
+			Handler:    _MovieCatalogService_ListChannels_Handler,
+		},
+		{
+			MethodName: "ListTopics",
+			Handler:    _MovieCatalogService_ListTopics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchMovies",
+			Handler:       _MovieCatalogService_SearchMovies_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamCatalog",
+			Handler:       _MovieCatalogService_StreamCatalog_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "moviecat.proto",
 }
@@ -0,0 +1,207 @@
+package moviecat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// FormatVersion is the version of the movie catalog format written by this
+// package. Readers accept all versions up to this one.
+const FormatVersion = 2
+
+// StreamContentType is the content type of a length-delimited movie catalog
+// stream, e.g. when served via HTTP.
+const StreamContentType = "application/x-protobuf; proto=moviecat.MovieCatalogHeader; delimited=true"
+
+// DefaultChunkSize is the number of movie entries per chunk if not set
+// otherwise.
+const DefaultChunkSize = 1000
+
+// maxMessageSize limits the size of a single message in the stream. It
+// protects the decoder from allocating huge buffers on corrupt input.
+const maxMessageSize = 64 << 20
+
+var errHeaderMissing = errors.New("movie catalog header not written")
+
+// Encoder writes a movie catalog as a stream of length-delimited messages.
+// The stream starts with a single MovieCatalogHeader followed by any number of
+// MovieEntryChunk messages. Each message is prefixed by its size encoded as
+// varint. Unlike marshalling a whole MovieCatalog the movies are written in
+// chunks, so neither the encoder nor the decoder have to keep the whole
+// catalog in memory.
+type Encoder struct {
+	w             *bufio.Writer
+	chunkSize     int
+	chunk         MovieEntryChunk
+	headerWritten bool
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:         bufio.NewWriter(w),
+		chunkSize: DefaultChunkSize,
+	}
+}
+
+// SetChunkSize sets the maximum number of movie entries per chunk.
+func (e *Encoder) SetChunkSize(n int) {
+	if n > 0 {
+		e.chunkSize = n
+	}
+}
+
+// EncodeHeader writes the header. It must be called exactly once before any
+// movie entry is encoded.
+func (e *Encoder) EncodeHeader(header *MovieCatalogHeader) error {
+	if e.headerWritten {
+		return fmt.Errorf("movie catalog header already written")
+	}
+
+	err := e.writeMessage(header)
+	if err != nil {
+		return err
+	}
+	e.headerWritten = true
+
+	return nil
+}
+
+// EncodeMovie adds the movie entry to the current chunk. Once the chunk is
+// full, it's written to the underlying writer.
+func (e *Encoder) EncodeMovie(movie *MovieEntry) error {
+	if !e.headerWritten {
+		return errHeaderMissing
+	}
+
+	e.chunk.Movies = append(e.chunk.Movies, movie)
+	if len(e.chunk.Movies) < e.chunkSize {
+		return nil
+	}
+
+	return e.writeChunk()
+}
+
+// Close writes the pending chunk and flushes the buffered data. It doesn't
+// close the underlying writer.
+func (e *Encoder) Close() error {
+	if !e.headerWritten {
+		return errHeaderMissing
+	}
+
+	if len(e.chunk.Movies) > 0 {
+		err := e.writeChunk()
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.w.Flush()
+}
+
+func (e *Encoder) writeChunk() error {
+	err := e.writeMessage(&e.chunk)
+	e.chunk.Movies = e.chunk.Movies[:0]
+	if err != nil {
+		return err
+	}
+
+	// Flush each chunk, so that clients can start decoding early
+	return e.w.Flush()
+}
+
+func (e *Encoder) writeMessage(msg proto.Message) error {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(b)))
+	_, err = e.w.Write(size[:n])
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(b)
+	return err
+}
+
+// Decoder reads a movie catalog stream written by an Encoder.
+type Decoder struct {
+	r          *bufio.Reader
+	headerRead bool
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// DecodeHeader reads the header of the stream. It must be called before any
+// chunk is decoded.
+func (d *Decoder) DecodeHeader() (*MovieCatalogHeader, error) {
+	if d.headerRead {
+		return nil, fmt.Errorf("movie catalog header already read")
+	}
+
+	header := &MovieCatalogHeader{}
+	err := d.readMessage(header)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Version < 1 || header.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported movie catalog version %d", header.Version)
+	}
+	d.headerRead = true
+
+	return header, nil
+}
+
+// DecodeChunk reads the next chunk of movie entries. At the end of the stream
+// io.EOF is returned.
+func (d *Decoder) DecodeChunk() (*MovieEntryChunk, error) {
+	if !d.headerRead {
+		return nil, fmt.Errorf("movie catalog header not read")
+	}
+
+	chunk := &MovieEntryChunk{}
+	err := d.readMessage(chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	return chunk, nil
+}
+
+func (d *Decoder) readMessage(msg proto.Message) error {
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		// A clean EOF is only expected before the size of the next message
+		return err
+	}
+
+	if size > maxMessageSize {
+		return fmt.Errorf("message size %d exceeds limit", size)
+	}
+
+	b := make([]byte, size)
+	_, err = io.ReadFull(d.r, b)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(b, msg)
+}
@@ -0,0 +1,178 @@
+package moviecat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// encodeTestStream encodes a header and the given number of movies in chunks
+// of the given size.
+func encodeTestStream(t *testing.T, movies, chunkSize int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetChunkSize(chunkSize)
+
+	err := enc.EncodeHeader(&MovieCatalogHeader{
+		Version:     FormatVersion,
+		Md5Hash:     []byte("0123456789abcdef0123456789abcdef"),
+		MoviesCount: int64(movies),
+		Channels:    []*ChannelEntry{{Version: 1, Id: 1, Name: "ARD"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < movies; i++ {
+		err := enc.EncodeMovie(&MovieEntry{Version: FormatVersion,
+			Id: strconv.Itoa(i), Title: "Movie " + strconv.Itoa(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// writeTestMessage writes a length-delimited message like the encoder.
+func writeTestMessage(t *testing.T, w io.Writer, msg proto.Message) {
+	t.Helper()
+
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var size [binary.MaxVarintLen64]byte
+	w.Write(size[:binary.PutUvarint(size[:], uint64(len(b)))])
+	w.Write(b)
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	dec := NewDecoder(bytes.NewReader(encodeTestStream(t, 7, 3)))
+
+	header, err := dec.DecodeHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.GetVersion() != FormatVersion || header.GetMoviesCount() != 7 ||
+		len(header.GetChannels()) != 1 {
+		t.Errorf("header = %v", header)
+	}
+
+	var sizes []int
+	var ids []string
+	for {
+		chunk, err := dec.DecodeChunk()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(chunk.GetMovies()))
+		for _, movie := range chunk.GetMovies() {
+			ids = append(ids, movie.GetId())
+		}
+	}
+
+	if len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+		t.Errorf("chunk sizes = %v, want [3 3 1]", sizes)
+	}
+	if got := strings.Join(ids, ","); got != "0,1,2,3,4,5,6" {
+		t.Errorf("movie IDs = %s, want 0,1,2,3,4,5,6", got)
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	data := encodeTestStream(t, 7, 3)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"within header", data[:5]},
+		{"within last chunk", data[:len(data)-3]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(tt.data))
+
+			_, err := dec.DecodeHeader()
+			for err == nil {
+				_, err = dec.DecodeChunk()
+			}
+			if err != io.ErrUnexpectedEOF {
+				t.Errorf("err = %v, want %v", err, io.ErrUnexpectedEOF)
+			}
+		})
+	}
+}
+
+func TestStreamOversizedMessage(t *testing.T) {
+	var buf bytes.Buffer
+	writeTestMessage(t, &buf, &MovieCatalogHeader{Version: FormatVersion})
+
+	var size [binary.MaxVarintLen64]byte
+	buf.Write(size[:binary.PutUvarint(size[:], maxMessageSize+1)])
+
+	dec := NewDecoder(&buf)
+	if _, err := dec.DecodeHeader(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := dec.DecodeChunk()
+	if err == nil || !strings.Contains(err.Error(), "exceeds limit") {
+		t.Errorf("err = %v, want size limit exceeded", err)
+	}
+}
+
+func TestStreamVersions(t *testing.T) {
+	tests := []struct {
+		version int32
+		ok      bool
+	}{
+		{0, false},
+		{1, true},
+		{FormatVersion, true},
+		{FormatVersion + 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.version)), func(t *testing.T) {
+			var buf bytes.Buffer
+			writeTestMessage(t, &buf, &MovieCatalogHeader{Version: tt.version})
+			writeTestMessage(t, &buf, &MovieEntryChunk{Movies: []*MovieEntry{
+				{Version: tt.version, Id: "1", Url: "https://example.org/1.mp4"}}})
+
+			dec := NewDecoder(&buf)
+			_, err := dec.DecodeHeader()
+			if !tt.ok {
+				if err == nil || !strings.Contains(err.Error(), "unsupported") {
+					t.Errorf("err = %v, want unsupported version", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chunk, err := dec.DecodeChunk()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(chunk.GetMovies()) != 1 || chunk.GetMovies()[0].GetUrl() != "https://example.org/1.mp4" {
+				t.Errorf("chunk = %v", chunk)
+			}
+		})
+	}
+}
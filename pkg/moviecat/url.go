@@ -0,0 +1,89 @@
+package moviecat
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompactURL encodes the URL relative to the base URL like the MediathekView
+// movie list does. The result is the length of the prefix shared with the base
+// URL and the remaining suffix separated by a pipe, e.g. "100|def.mp4". If the
+// compact form isn't shorter than the URL, the URL is returned as is. See
+// ExpandURL for the reverse operation.
+func CompactURL(baseURL, url string) string {
+	if url == "" || baseURL == "" {
+		return url
+	}
+
+	// The prefix must not cover the whole base URL, otherwise ExpandURL
+	// considers the index as invalid
+	n := len(baseURL) - 1
+	if len(url) < n {
+		n = len(url)
+	}
+
+	i := 0
+	for i < n && baseURL[i] == url[i] {
+		i++
+	}
+
+	result := strconv.Itoa(i) + "|" + url[i:]
+
+	// A pipe in a full URL would be mistaken for the compact form, so in this
+	// case the URL must always be compacted
+	if len(result) >= len(url) && !strings.Contains(url, "|") {
+		return url
+	}
+
+	return result
+}
+
+// ExpandURL builds the full URL from an URL given in the compact form. See
+// CompactURL for details. If the URL doesn't contain a pipe, it's considered
+// to be a full URL and returned as is. An invalid compact URL results in an
+// empty string.
+func ExpandURL(baseURL, url string) string {
+	var result = strings.Trim(url, " ")
+	if result == "" || baseURL == "" {
+		return result
+	}
+
+	// Check if there's a pipe with an index, otherwise return the given URL
+	i := strings.Index(result, "|")
+	if i == -1 {
+		return result
+	}
+
+	// Get the index for the baseURL
+	j, err := strconv.Atoi(result[:i])
+	if err != nil {
+		return ""
+	}
+	if j < 0 || j >= len(baseURL) {
+		return ""
+	}
+
+	// Get URL path until index
+	result = baseURL[:j] + result[i+1:]
+
+	return result
+}
+
+// CompactURLs replaces the subtitle, small format, HD format and history URLs
+// of the entry by their compact form relative to the entry URL.
+func (m *MovieEntry) CompactURLs() {
+	m.SubtitleUrl = CompactURL(m.Url, m.SubtitleUrl)
+	m.SmallFormatUrl = CompactURL(m.Url, m.SmallFormatUrl)
+	m.HdFormatUrl = CompactURL(m.Url, m.HdFormatUrl)
+	m.HistoryUrl = CompactURL(m.Url, m.HistoryUrl)
+}
+
+// ExpandURLs reverses CompactURLs. It must only be called on entries of a
+// catalog with compact URLs, because full URLs containing a pipe would be
+// mangled otherwise.
+func (m *MovieEntry) ExpandURLs() {
+	m.SubtitleUrl = ExpandURL(m.Url, m.SubtitleUrl)
+	m.SmallFormatUrl = ExpandURL(m.Url, m.SmallFormatUrl)
+	m.HdFormatUrl = ExpandURL(m.Url, m.HdFormatUrl)
+	m.HistoryUrl = ExpandURL(m.Url, m.HistoryUrl)
+}
@@ -0,0 +1,70 @@
+package moviecat
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCompactURLRoundTrip(t *testing.T) {
+	const base = "https://pdvideosdaserste-a.akamaihd.net/int/2024/03/01/abc/960-1.mp4"
+
+	tests := []struct {
+		name    string
+		url     string
+		compact string
+	}{
+		{"empty", "", ""},
+		{"shared prefix", "https://pdvideosdaserste-a.akamaihd.net/int/2024/03/01/abc/1280-1.mp4",
+			"59|1280-1.mp4"},
+		{"no shared prefix", "rtmp://www.ardmediathek.de/sub.xml",
+			"rtmp://www.ardmediathek.de/sub.xml"},
+		{"short suffix kept", "https://pdvideosdaserste-a.akamaihd.net/int/2024/03/01/abc/960-1.mp3",
+			"67|3"},
+		{"same as base", base, "67|4"},
+		{"prefix of base", "https://pdvideosdaserste-a.akamaihd.net/int/",
+			"44|"},
+		{"pipe in URL", "rtmp://x/a|b", "0|rtmp://x/a|b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compact := CompactURL(base, tt.url)
+			if compact != tt.compact {
+				t.Errorf("CompactURL(%q) = %q, want %q", tt.url, compact, tt.compact)
+			}
+			if got := ExpandURL(base, compact); got != tt.url {
+				t.Errorf("ExpandURL(%q) = %q, want %q", compact, got, tt.url)
+			}
+		})
+	}
+}
+
+func TestCompactURLRoundTripRandom(t *testing.T) {
+	const alphabet = "abc/.:|-0123456789"
+
+	rnd := rand.New(rand.NewSource(1))
+	randomString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 10000; i++ {
+		base := "https://" + randomString(rnd.Intn(20))
+
+		// Derive the URL from the base, so most URLs share a prefix with it
+		prefix := base[:rnd.Intn(len(base)+1)]
+		url := prefix + randomString(rnd.Intn(20))
+		if rnd.Intn(10) == 0 {
+			url = ""
+		}
+
+		compact := CompactURL(base, url)
+		if got := ExpandURL(base, compact); got != url {
+			t.Fatalf("ExpandURL(%q, CompactURL(%q, %q) = %q) = %q",
+				base, base, url, compact, got)
+		}
+	}
+}
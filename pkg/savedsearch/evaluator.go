@@ -0,0 +1,110 @@
+// Package savedsearch persists searches which are evaluated against the
+// movies added by each catalog switch. New matches are recorded for the Atom
+// feed of the search and pushed by the notifier of its kind.
+package savedsearch
+
+import (
+	"context"
+	"log"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+// MaxMatches limits the matches recorded per search and catalog, so overly
+// broad searches don't flood the receivers.
+const MaxMatches = 500
+
+// Evaluator evaluates the saved searches after a catalog became current.
+type Evaluator struct {
+	searches  *Store
+	catalogs  *catalog.Store
+	notifiers map[string]Notifier
+}
+
+// NewEvaluator creates an evaluator for the searches of the given store. The
+// notifiers are registered by kind, searches of kinds without notifier only
+// record their matches.
+func NewEvaluator(searches *Store, catalogs *catalog.Store) *Evaluator {
+	return &Evaluator{
+		searches:  searches,
+		catalogs:  catalogs,
+		notifiers: map[string]Notifier{},
+	}
+}
+
+// Register sets the notifier of the given kind.
+func (e *Evaluator) Register(kind string, n Notifier) {
+	e.notifiers[kind] = n
+}
+
+// CatalogActivated evaluates all saved searches against the movies added by
+// switching to the catalog with the given hash. Failed notifications are
+// logged but don't cause an error, the matches are recorded anyway.
+func (e *Evaluator) CatalogActivated(ctx context.Context, hash string) error {
+	searches, err := e.searches.FindAll()
+	if err != nil || len(searches) == 0 {
+		return err
+	}
+
+	// The first catalog has no change log, nothing counts as added then
+	summary, err := e.catalogs.SummarizeChanges(ctx, hash)
+	if err != nil || summary.Added == 0 {
+		return err
+	}
+
+	cat, err := e.catalogs.FindCatalog(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	channels, err := e.catalogs.FindAllChannels(ctx, cat.Hash)
+	if err != nil {
+		return err
+	}
+
+	for _, search := range searches {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		filter := search.Filter()
+		filter.AddedIn = cat.Hash
+		filter.Sort = catalog.SortByPublishedAt
+		filter.Limit = MaxMatches
+
+		movies, err := e.catalogs.FindMovies(ctx, cat.Hash, filter)
+		if err != nil {
+			return err
+		}
+		if len(movies) == 0 {
+			continue
+		}
+
+		movies, err = e.searches.RecordMatches(search.ID, cat.Hash, movies)
+		if err != nil {
+			return err
+		}
+
+		notifier, ok := e.notifiers[search.Notifier]
+		if len(movies) == 0 || !ok {
+			if len(movies) > 0 && search.Notifier != NotifyFeed {
+				log.Printf("savedsearch: no %s notifier configured for search %d",
+					search.Notifier, search.ID)
+			}
+			continue
+		}
+
+		err = notifier.Notify(ctx, Notification{
+			Search:   search,
+			Catalog:  cat,
+			Movies:   movies,
+			Channels: channels,
+		})
+		if err != nil {
+			log.Printf("savedsearch: failed to notify search %d via %s: %s",
+				search.ID, search.Notifier, err)
+		}
+	}
+
+	return nil
+}
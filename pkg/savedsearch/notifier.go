@@ -0,0 +1,263 @@
+package savedsearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+	_ "time/tzdata" // Embed the zoneinfo for Europe/Berlin
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/webhook"
+)
+
+// EventSearchMatched is sent to webhooks of saved searches with new matches.
+const EventSearchMatched = "search.matched"
+
+// DefaultSMTPTimeout limits the time sending a mail may take unless the
+// context has an earlier deadline.
+const DefaultSMTPTimeout = 30 * time.Second
+
+// berlin is the time zone the broadcast times are shown in, like the movie
+// list does.
+var berlin = mustLoadLocation("Europe/Berlin")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Notification holds the new matches of a saved search in a catalog. The
+// channels map the channel IDs of the movies to their names.
+type Notification struct {
+	Search   Search
+	Catalog  catalog.Catalog
+	Movies   []catalog.Movie
+	Channels map[int64]string
+}
+
+// Notifier delivers the matches of saved searches with a certain notifier
+// kind.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// MatchedMovie is a movie in the payload of a webhook notification.
+type MatchedMovie struct {
+	StableID    string    `json:"stableId"`
+	Channel     string    `json:"channel"`
+	Topic       string    `json:"topic"`
+	Title       string    `json:"title"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Duration    int64     `json:"duration"`
+	URL         string    `json:"url"`
+	WebsiteURL  string    `json:"websiteUrl,omitempty"`
+}
+
+// Payload is the JSON body of a webhook notification.
+type Payload struct {
+	Event    string         `json:"event"`
+	SearchID int64          `json:"searchId"`
+	Name     string         `json:"name"`
+	Hash     string         `json:"hash"`
+	Movies   []MatchedMovie `json:"movies"`
+	SentAt   time.Time      `json:"sentAt"`
+}
+
+// WebhookNotifier posts the matches to the target URL of the search. The
+// payload is signed with the secret of the search like the payloads of the
+// catalog webhooks.
+type WebhookNotifier struct {
+	delivery *webhook.Notifier
+}
+
+// NewWebhookNotifier creates a webhook notifier delivering with the given
+// webhook notifier, which provides the retries.
+func NewWebhookNotifier(delivery *webhook.Notifier) *WebhookNotifier {
+	return &WebhookNotifier{delivery: delivery}
+}
+
+// Notify implements Notifier.
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	payload := Payload{
+		Event:    EventSearchMatched,
+		SearchID: n.Search.ID,
+		Name:     n.Search.Name,
+		Hash:     n.Catalog.Hash,
+		SentAt:   time.Now(),
+	}
+	for _, m := range n.Movies {
+		payload.Movies = append(payload.Movies, MatchedMovie{
+			StableID:    m.StableID,
+			Channel:     n.Channels[m.ChannelID],
+			Topic:       m.Topic,
+			Title:       m.Title,
+			PublishedAt: m.PublishedAt,
+			Duration:    m.Duration,
+			URL:         m.URL,
+			WebsiteURL:  m.WebsiteURL,
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	d := w.delivery.Deliver(ctx, n.Search.Target, n.Search.secret,
+		EventSearchMatched, body)
+	if d.DeliveredAt == nil {
+		return fmt.Errorf("delivery failed after %d attempts: %s", d.Attempts,
+			d.Error)
+	}
+
+	return nil
+}
+
+// SMTPConfig configures the server sending the email notifications. The
+// address has the form host:port. Without username no authentication is
+// used.
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// EmailNotifier mails the matches to the target address of the search.
+type EmailNotifier struct {
+	cfg SMTPConfig
+}
+
+// NewEmailNotifier creates an email notifier sending via the given server.
+func NewEmailNotifier(cfg SMTPConfig) (*EmailNotifier, error) {
+	if cfg.Addr == "" || cfg.From == "" {
+		return nil, errors.New("SMTP address and sender are required")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Addr); err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %s", cfg.Addr, err)
+	}
+
+	return &EmailNotifier{cfg: cfg}, nil
+}
+
+// Notify implements Notifier.
+func (e *EmailNotifier) Notify(ctx context.Context, n Notification) error {
+	msg, err := e.message(n)
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, n.Search.Target, msg)
+}
+
+// send delivers the message using a connection bound to the context, so a
+// stalled server can't block the evaluation of the saved searches. STARTTLS
+// is used if the server supports it.
+func (e *EmailNotifier) send(ctx context.Context, to string, msg []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > DefaultSMTPTimeout {
+		deadline = time.Now().Add(DefaultSMTPTimeout)
+	}
+
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", e.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Canceling the context interrupts blocked reads and writes
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(e.cfg.Addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if e.cfg.Username != "" {
+		auth := smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// message formats the mail listing the new matches as quoted-printable
+// plain text.
+func (e *EmailNotifier) message(n Notification) ([]byte, error) {
+	var buf bytes.Buffer
+
+	subject := fmt.Sprintf("%d new movies for %s", len(n.Movies), n.Search.Name)
+	if len(n.Movies) == 1 {
+		subject = "1 new movie for " + n.Search.Name
+	}
+
+	fmt.Fprintf(&buf, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", n.Search.Target)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	for _, m := range n.Movies {
+		lines := []string{
+			fmt.Sprintf("%s - %s", m.Topic, m.Title),
+			fmt.Sprintf("%s, %s, %d min", n.Channels[m.ChannelID],
+				m.PublishedAt.In(berlin).Format("02.01.2006 15:04"), m.Duration/60),
+			m.URL,
+		}
+		if m.WebsiteURL != "" {
+			lines = append(lines, m.WebsiteURL)
+		}
+		if _, err := fmt.Fprintf(qp, "%s\r\n\r\n", strings.Join(lines, "\r\n")); err != nil {
+			return nil, err
+		}
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,75 @@
+package savedsearch
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+func testNotification() Notification {
+	return Notification{
+		Search: Search{Name: "Tatort", Target: "user@example.org"},
+		Movies: []catalog.Movie{{
+			ChannelID:   1,
+			Topic:       "Tatort",
+			Title:       "Das Team",
+			PublishedAt: time.Date(2024, 3, 1, 19, 15, 0, 0, time.UTC),
+			Duration:    5400,
+			URL:         "https://example.org/movie.mp4",
+		}},
+		Channels: map[int64]string{1: "ARD"},
+	}
+}
+
+func TestEmailMessageUsesBerlinTime(t *testing.T) {
+	e, err := NewEmailNotifier(SMTPConfig{Addr: "localhost:25", From: "mdthk@example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := e.message(testNotification())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ARD, 01.03.2024 20:15, 90 min"; !strings.Contains(string(msg), want) {
+		t.Errorf("message doesn't contain %q:\n%s", want, msg)
+	}
+}
+
+func TestEmailNotifyHonorsContext(t *testing.T) {
+	// The server accepts connections but never greets the client
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	e, err := NewEmailNotifier(SMTPConfig{Addr: lis.Addr().String(), From: "mdthk@example.org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := e.Notify(ctx, testNotification()); err == nil {
+		t.Fatal("Notify succeeded without server")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Notify returned after %s, want about 50ms", d)
+	}
+}
@@ -0,0 +1,323 @@
+package savedsearch
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+// undefinedTable is the PostgreSQL error code of a missing table.
+const undefinedTable = "42P01"
+
+// Notifier kinds of saved searches. Matches of all saved searches are
+// recorded for their Atom feed, the feed kind doesn't push them anywhere.
+const (
+	NotifyFeed    = "feed"
+	NotifyWebhook = "webhook"
+	NotifyEmail   = "email"
+)
+
+// ErrSearchNotFound is returned if a saved search doesn't exist.
+var ErrSearchNotFound = errors.New("saved search not found")
+
+// Search is a persisted search evaluated against the movies added by each
+// import. The target is the URL of the webhook or the email address. The
+// feed token identifies the Atom feed of the matches without revealing the
+// search.
+type Search struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Query       string    `json:"query,omitempty"`
+	ChannelID   int64     `json:"channelId,omitempty"`
+	TopicID     int64     `json:"topicId,omitempty"`
+	MinDuration int64     `json:"minDuration,omitempty"`
+	MaxDuration int64     `json:"maxDuration,omitempty"`
+	Notifier    string    `json:"notifier"`
+	Target      string    `json:"target,omitempty"`
+	FeedToken   string    `json:"feedToken"`
+	CreatedAt   time.Time `json:"createdAt"`
+	secret      string
+}
+
+// Filter returns the movie filter of the search.
+func (s Search) Filter() catalog.MovieFilter {
+	return catalog.MovieFilter{
+		Query:       s.Query,
+		ChannelID:   s.ChannelID,
+		TopicID:     s.TopicID,
+		MinDuration: s.MinDuration,
+		MaxDuration: s.MaxDuration,
+	}
+}
+
+// ValidNotifier checks if the notifier kind is supported.
+func ValidNotifier(kind string) bool {
+	switch kind {
+	case NotifyFeed, NotifyWebhook, NotifyEmail:
+		return true
+	}
+	return false
+}
+
+// Match is a movie matching a saved search. The movie is copied, so the
+// matches outlive the catalog they were found in.
+type Match struct {
+	SearchID    int64
+	CatalogHash string
+	MatchedAt   time.Time
+	Movie       catalog.Movie
+}
+
+// Store manages the saved searches and their matches in the database.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new saved search store on top of the given database.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// createTables creates the tables of the saved searches and their matches if
+// they don't exist. Matches are removed together with their search.
+func (s *Store) createTables() error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS saved_searches (
+			id bigserial PRIMARY KEY,
+			name text NOT NULL,
+			query text NOT NULL DEFAULT '',
+			channel_id bigint NOT NULL DEFAULT 0,
+			topic_id bigint NOT NULL DEFAULT 0,
+			min_duration bigint NOT NULL DEFAULT 0,
+			max_duration bigint NOT NULL DEFAULT 0,
+			notifier varchar(16) NOT NULL,
+			target text NOT NULL DEFAULT '',
+			secret text NOT NULL DEFAULT '',
+			feed_token char(32) NOT NULL UNIQUE,
+			created_at timestamptz NOT NULL DEFAULT now()
+		)`,
+		`CREATE TABLE IF NOT EXISTS saved_search_matches (
+			id bigserial PRIMARY KEY,
+			search_id bigint NOT NULL REFERENCES saved_searches ON DELETE CASCADE,
+			catalog_hash varchar(32) NOT NULL,
+			matched_at timestamptz NOT NULL,
+			stable_id char(32) NOT NULL,
+			channel_id bigint,
+			topic text,
+			title text,
+			descr text,
+			published_at timestamptz,
+			duration integer,
+			size bigint,
+			url varchar(2047),
+			website_url varchar(2047),
+			small_format_url varchar(2047),
+			hd_format_url varchar(2047),
+			geo_codes text[],
+			UNIQUE (search_id, stable_id)
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create saves the search. A random secret signing the webhook payloads and
+// the feed token are generated. The secret is returned.
+func (s *Store) Create(search Search) (Search, string, error) {
+	err := s.createTables()
+	if err != nil {
+		return search, "", err
+	}
+
+	secret, err := randomHex(24)
+	if err != nil {
+		return search, "", err
+	}
+	token, err := randomHex(16)
+	if err != nil {
+		return search, "", err
+	}
+
+	err = s.db.QueryRow(
+		`INSERT INTO saved_searches (name, query, channel_id, topic_id,
+            min_duration, max_duration, notifier, target, secret, feed_token)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        RETURNING id, created_at`, search.Name, search.Query, search.ChannelID,
+		search.TopicID, search.MinDuration, search.MaxDuration,
+		search.Notifier, search.Target, secret, token).Scan(&search.ID,
+		&search.CreatedAt)
+	if err != nil {
+		return search, "", err
+	}
+	search.secret = secret
+	search.FeedToken = token
+
+	return search, secret, nil
+}
+
+// FindAll returns all saved searches ordered by ID. Reading doesn't create
+// the tables, a missing table means nothing has been saved yet.
+func (s *Store) FindAll() ([]Search, error) {
+	return s.find("", nil)
+}
+
+// FindByFeedToken returns the saved search with the given feed token.
+func (s *Store) FindByFeedToken(token string) (Search, error) {
+	searches, err := s.find(" WHERE feed_token = $1", []interface{}{token})
+	if err != nil {
+		return Search{}, err
+	}
+	if len(searches) == 0 {
+		return Search{}, ErrSearchNotFound
+	}
+
+	return searches[0], nil
+}
+
+func (s *Store) find(where string, args []interface{}) ([]Search, error) {
+	var result []Search
+
+	rows, err := s.db.Query(
+		`SELECT id, name, query, channel_id, topic_id, min_duration,
+            max_duration, notifier, target, secret, feed_token, created_at
+        FROM saved_searches`+where+` ORDER BY id`, args...)
+	if isUndefinedTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var search Search
+		if err := rows.Scan(&search.ID, &search.Name, &search.Query,
+			&search.ChannelID, &search.TopicID, &search.MinDuration,
+			&search.MaxDuration, &search.Notifier, &search.Target,
+			&search.secret, &search.FeedToken, &search.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		result = append(result, search)
+	}
+
+	return result, rows.Err()
+}
+
+// Delete removes the saved search with the given ID and its matches.
+func (s *Store) Delete(id int64) error {
+	err := s.createTables()
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec("DELETE FROM saved_searches WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSearchNotFound
+	}
+
+	return nil
+}
+
+// RecordMatches stores the matches of a search. Movies already matched are
+// skipped, e.g. if a catalog is activated again. It returns the movies
+// which matched for the first time.
+func (s *Store) RecordMatches(searchID int64, hash string, movies []catalog.Movie) ([]catalog.Movie, error) {
+	var result []catalog.Movie
+
+	err := s.createTables()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, m := range movies {
+		res, err := s.db.Exec(
+			`INSERT INTO saved_search_matches (search_id, catalog_hash,
+                matched_at, stable_id, channel_id, topic, title, descr,
+                published_at, duration, size, url, website_url,
+                small_format_url, hd_format_url, geo_codes)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+                $14, $15, $16)
+            ON CONFLICT (search_id, stable_id) DO NOTHING`, searchID, hash,
+			now, m.StableID, m.ChannelID, m.Topic, m.Title, m.Descr,
+			m.PublishedAt, m.Duration, m.Size, m.URL, m.WebsiteURL,
+			m.SmallFormatURL, m.HDFormatURL, pq.StringArray(m.GeoCodes))
+		if err != nil {
+			return result, err
+		}
+
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			result = append(result, m)
+		}
+	}
+
+	return result, nil
+}
+
+// FindMatches returns the most recent matches of the search, newest first.
+func (s *Store) FindMatches(searchID int64, limit int) ([]Match, error) {
+	var result []Match
+
+	rows, err := s.db.Query(
+		`SELECT catalog_hash, matched_at, stable_id, channel_id, topic, title,
+            descr, published_at, duration, size, url, website_url,
+            small_format_url, hd_format_url, geo_codes
+        FROM saved_search_matches WHERE search_id = $1
+        ORDER BY matched_at DESC, published_at DESC, id LIMIT $2`,
+		searchID, limit)
+	if isUndefinedTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		match := Match{SearchID: searchID}
+		m := &match.Movie
+		if err := rows.Scan(&match.CatalogHash, &match.MatchedAt, &m.StableID,
+			&m.ChannelID, &m.Topic, &m.Title, &m.Descr, &m.PublishedAt,
+			&m.Duration, &m.Size, &m.URL, &m.WebsiteURL, &m.SmallFormatURL,
+			&m.HDFormatURL, pq.Array(&m.GeoCodes)); err != nil {
+			return nil, err
+		}
+
+		result = append(result, match)
+	}
+
+	return result, rows.Err()
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func isUndefinedTable(err error) bool {
+	e, ok := err.(*pq.Error)
+	return ok && e.Code == undefinedTable
+}
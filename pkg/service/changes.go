@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+// Page sizes of the change log
+const (
+	defaultChangesLimit = 1000
+	maxChangesLimit     = 10000
+)
+
+type changeResource struct {
+	Kind        string `json:"kind"`
+	StableID    string `json:"id"`
+	Channel     string `json:"channel,omitempty"`
+	Topic       string `json:"topic,omitempty"`
+	Title       string `json:"title,omitempty"`
+	PublishedAt int64  `json:"publishedAt,omitempty"`
+	Duration    int64  `json:"duration,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Catalog     string `json:"catalog"`
+	ChangedAt   int64  `json:"changedAt"`
+}
+
+type changeListResource struct {
+	Meta struct {
+		Since        int64  `json:"since"`
+		Catalog      string `json:"catalog"`
+		ChangesCount int    `json:"changesCount"`
+	} `json:"meta"`
+	Changes []changeResource `json:"changes"`
+}
+
+// handleChanges serves the movies added, removed or changed by the catalog
+// imports since the given time. The change log is only extended when the
+// current catalog changes, so the catalog hash serves as E-Tag.
+func (svc *Service) handleChanges(w http.ResponseWriter, r *http.Request) {
+	var resource changeListResource
+
+	filter, err := parseChangeFilter(r)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cat, ok := svc.currentCatalog(w, r)
+	if !ok {
+		return
+	}
+
+	if notModified(w, r, cat) {
+		return
+	}
+
+	resource.Meta.Since = filter.Since.Unix()
+	resource.Meta.Catalog = cat.Hash
+	resource.Meta.ChangesCount, err = svc.store.CountChanges(r.Context(), filter)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	changes, err := svc.store.FindChanges(r.Context(), filter)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	resource.Changes = make([]changeResource, 0, len(changes))
+	for _, c := range changes {
+		resource.Changes = append(resource.Changes, changeToResource(c))
+	}
+
+	setPaginationLinks(w, r, filter.Limit, filter.Offset,
+		resource.Meta.ChangesCount)
+	setCacheHeaders(w, cat)
+
+	writeJSON(w, http.StatusOK, resource)
+}
+
+// parseChangeFilter builds the change filter from the query parameters. The
+// since parameter is required and accepts RFC 3339 timestamps as well as unix
+// timestamps.
+func parseChangeFilter(r *http.Request) (catalog.ChangeFilter, error) {
+	var filter catalog.ChangeFilter
+	queryParams := r.URL.Query()
+
+	since := queryParams.Get("since")
+	if since == "" {
+		return filter, fmt.Errorf("missing parameter since")
+	}
+	if unix, err := strconv.ParseInt(since, 10, 64); err == nil {
+		filter.Since = time.Unix(unix, 0)
+	} else if t, err := time.Parse(time.RFC3339, since); err == nil {
+		filter.Since = t
+	} else {
+		return filter, fmt.Errorf("invalid value %q for parameter since", since)
+	}
+
+	filter.Kind = queryParams.Get("kind")
+	if !catalog.ValidChangeKind(filter.Kind) {
+		return filter, fmt.Errorf("invalid value %q for parameter kind", filter.Kind)
+	}
+
+	limit, err := parseIntParam(queryParams, "limit")
+	if err != nil {
+		return filter, err
+	}
+	if limit == 0 {
+		limit = defaultChangesLimit
+	}
+	if limit > maxChangesLimit {
+		limit = maxChangesLimit
+	}
+	filter.Limit = int(limit)
+
+	offset, err := parseIntParam(queryParams, "offset")
+	if err != nil {
+		return filter, err
+	}
+	filter.Offset = int(offset)
+
+	return filter, nil
+}
+
+func changeToResource(c catalog.Change) changeResource {
+	result := changeResource{
+		Kind:      c.Kind,
+		StableID:  c.StableID,
+		Channel:   c.Channel,
+		Topic:     c.Topic,
+		Title:     c.Title,
+		Duration:  c.Duration,
+		URL:       c.URL,
+		Catalog:   c.CatalogHash,
+		ChangedAt: c.ChangedAt.Unix(),
+	}
+	if !c.PublishedAt.IsZero() {
+		result.PublishedAt = c.PublishedAt.Unix()
+	}
+
+	return result
+}
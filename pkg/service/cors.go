@@ -0,0 +1,118 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/negroni"
+)
+
+// CORSOptions configures the cross-origin resource sharing of the API. CORS is
+// disabled if no origins are allowed.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to call the API, "*" allows
+	// any origin.
+	AllowedOrigins []string
+
+	// AllowedMethods and AllowedHeaders are announced in preflight responses.
+	AllowedMethods []string
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers readable by browser clients.
+	ExposedHeaders []string
+
+	// MaxAge is the time browsers may cache preflight responses.
+	MaxAge time.Duration
+}
+
+// Defaults of the CORS options, which apply if the options are empty
+var (
+	DefaultCORSMethods        = []string{"GET", "OPTIONS"}
+	DefaultCORSHeaders        = []string{"Accept", "If-None-Match", apiKeyHeader}
+	DefaultCORSExposedHeaders = []string{"ETag", "Link", "Retry-After"}
+	DefaultCORSMaxAge         = 10 * time.Minute
+)
+
+func (opts CORSOptions) withDefaults() CORSOptions {
+	if len(opts.AllowedMethods) == 0 {
+		opts.AllowedMethods = DefaultCORSMethods
+	}
+	if len(opts.AllowedHeaders) == 0 {
+		opts.AllowedHeaders = DefaultCORSHeaders
+	}
+	if len(opts.ExposedHeaders) == 0 {
+		opts.ExposedHeaders = DefaultCORSExposedHeaders
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultCORSMaxAge
+	}
+	return opts
+}
+
+// CORS returns a negroni middleware adding the CORS headers for allowed
+// origins and answering preflight requests. It must be added in front of the
+// authentication, so preflight requests and rejected requests are handled as
+// well.
+func (svc *Service) CORS() negroni.Handler {
+	opts := svc.opts.CORS
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge / time.Second))
+
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if len(opts.AllowedOrigins) == 0 {
+			next(rw, r)
+			return
+		}
+
+		// The response depends on the origin even if the request has none,
+		// otherwise caches would serve it to cross-origin requests as well
+		h := rw.Header()
+		h.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next(rw, r)
+			return
+		}
+
+		allowed, ok := allowedOrigin(opts.AllowedOrigins, origin)
+		if !ok {
+			next(rw, r)
+			return
+		}
+		h.Set("Access-Control-Allow-Origin", allowed)
+
+		preflight := r.Method == http.MethodOptions &&
+			r.Header.Get("Access-Control-Request-Method") != ""
+		if !preflight {
+			h.Set("Access-Control-Expose-Headers", exposed)
+			next(rw, r)
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", methods)
+		h.Set("Access-Control-Allow-Headers", headers)
+		h.Set("Access-Control-Max-Age", maxAge)
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// allowedOrigin returns the value of the Access-Control-Allow-Origin header
+// for the given origin.
+func allowedOrigin(origins []string, origin string) (string, bool) {
+	for _, o := range origins {
+		if o == "*" {
+			return "*", true
+		}
+		if strings.EqualFold(o, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
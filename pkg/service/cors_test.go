@@ -0,0 +1,27 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSVaryWithoutOrigin(t *testing.T) {
+	svc := &Service{opts: Options{CORS: CORSOptions{
+		AllowedOrigins: []string{"https://example.org"},
+	}.withDefaults()}}
+
+	handler := svc.CORS()
+	for _, origin := range []string{"", "https://example.org", "https://example.com"} {
+		rw := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/movies", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		handler.ServeHTTP(rw, r, func(http.ResponseWriter, *http.Request) {})
+
+		if vary := rw.Header().Get("Vary"); vary != "Origin" {
+			t.Errorf("origin %q: Vary = %q, want Origin", origin, vary)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/feed"
+)
+
+// Feed sizes, the size can be chosen by the limit parameter
+const (
+	defaultFeedSize = 50
+	maxFeedSize     = 500
+)
+
+// feedTTL is the time in minutes RSS readers should cache the feed.
+const feedTTL = 60
+
+// bytesPerMB converts the size of the movie list to bytes. The list only
+// contains rounded sizes, so enclosure lengths are approximations.
+const bytesPerMB = 1000 * 1000
+
+// movieGUIDPrefix turns the stable ID of a movie into a globally unique ID.
+const movieGUIDPrefix = "urn:mdthk:movie:"
+
+// Feed formats, podcasts are RSS feeds with iTunes and Podcasting 2.0 tags
+const (
+	feedFormatAtom    = "atom"
+	feedFormatRSS     = "rss"
+	feedFormatPodcast = "podcast"
+	feedFormatM3U8    = "m3u8"
+)
+
+// feedInfo describes the feed independent of its format.
+type feedInfo struct {
+	id       string
+	title    string
+	subtitle string
+	quality  string
+	filter   catalog.MovieFilter
+}
+
+func (svc *Service) handleTopicFeed(w http.ResponseWriter, r *http.Request) {
+	svc.handleEntryFeed(w, r, "topic", svc.store.FindTopic,
+		func(f *catalog.MovieFilter, id int64) { f.TopicID = id },
+		mux.Vars(r)["format"])
+}
+
+func (svc *Service) handleChannelFeed(w http.ResponseWriter, r *http.Request) {
+	svc.handleEntryFeed(w, r, "channel", svc.store.FindChannel,
+		func(f *catalog.MovieFilter, id int64) { f.ChannelID = id },
+		mux.Vars(r)["format"])
+}
+
+// handleTopicPodcast serves the feed of a topic as podcast, so shows can be
+// subscribed to in podcast apps.
+func (svc *Service) handleTopicPodcast(w http.ResponseWriter, r *http.Request) {
+	svc.handleEntryFeed(w, r, "topic", svc.store.FindTopic,
+		func(f *catalog.MovieFilter, id int64) { f.TopicID = id },
+		feedFormatPodcast)
+}
+
+// handleEntryFeed serves the feed of a channel or topic. The IDs of channels
+// and topics are derived from their names, so the feed URLs remain valid
+// across imports.
+func (svc *Service) handleEntryFeed(w http.ResponseWriter, r *http.Request, kind string,
+	find func(ctx context.Context, schema string, id int64) (string, error),
+	restrict func(f *catalog.MovieFilter, id int64), format string) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		writeProblem(w, http.StatusNotFound, "")
+		return
+	}
+
+	info, ok := svc.parseFeedInfo(w, r)
+	if !ok {
+		return
+	}
+	restrict(&info.filter, id)
+
+	cat, ok := svc.currentCatalog(w, r)
+	if !ok {
+		return
+	}
+	if notModified(w, r, cat) {
+		return
+	}
+
+	name, err := find(r.Context(), cat.Hash, id)
+	if err == catalog.ErrEntryNotFound {
+		writeProblem(w, http.StatusNotFound,
+			fmt.Sprintf("The %s %d doesn't exist.", kind, id))
+		return
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	info.id = fmt.Sprintf("urn:mdthk:%s:%d", kind, id)
+	info.title = name
+	info.subtitle = fmt.Sprintf("Neue Sendungen: %s", name)
+
+	svc.writeFeed(w, r, cat, info, format)
+}
+
+func (svc *Service) handleSearchFeed(w http.ResponseWriter, r *http.Request) {
+	svc.handleQueryFeed(w, r, mux.Vars(r)["format"])
+}
+
+// handleQueryFeed serves the feed of the movies matching the search query.
+func (svc *Service) handleQueryFeed(w http.ResponseWriter, r *http.Request, format string) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeProblem(w, http.StatusBadRequest, "The parameter q is required.")
+		return
+	}
+
+	info, ok := svc.parseFeedInfo(w, r)
+	if !ok {
+		return
+	}
+	info.filter.Query = query
+	info.id = "urn:mdthk:search:" + url.QueryEscape(query)
+	info.title = fmt.Sprintf("Suche: %s", query)
+	info.subtitle = fmt.Sprintf("Neue Sendungen zur Suche nach %q", query)
+
+	cat, ok := svc.currentCatalog(w, r)
+	if !ok {
+		return
+	}
+	if notModified(w, r, cat) {
+		return
+	}
+
+	svc.writeFeed(w, r, cat, info, format)
+}
+
+// parseFeedInfo parses the parameters common to all feeds. On error a problem
+// is written and false is returned.
+func (svc *Service) parseFeedInfo(w http.ResponseWriter, r *http.Request) (feedInfo, bool) {
+	var info feedInfo
+	var ok bool
+
+	info.quality = r.URL.Query().Get("quality")
+	if !catalog.ValidQuality(info.quality) {
+		writeProblem(w, http.StatusBadRequest,
+			fmt.Sprintf("invalid quality %q", info.quality))
+		return info, false
+	}
+
+	limit, err := parseIntParam(r.URL.Query(), "limit")
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, err.Error())
+		return info, false
+	}
+	if limit == 0 {
+		limit = defaultFeedSize
+	}
+	if limit > maxFeedSize {
+		limit = maxFeedSize
+	}
+
+	info.filter.Sort = catalog.SortByPublishedAtDesc
+	info.filter.Limit = int(limit)
+
+	// Feeds can't flag movies, so geo-blocked movies are always hidden
+	info.filter.Region, ok = svc.requestRegion(w, r)
+	if !ok {
+		return info, false
+	}
+
+	return info, true
+}
+
+// writeFeed writes the newest movies matching the feed filter as RSS, podcast
+// or Atom feed or as M3U8 playlist.
+func (svc *Service) writeFeed(w http.ResponseWriter, r *http.Request, cat catalog.Catalog, info feedInfo, format string) {
+	movies, err := svc.store.FindMovies(r.Context(), cat.Hash, info.filter)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	if format == feedFormatM3U8 {
+		w.Header().Set("Content-Type", feed.M3UContentType)
+		setCacheHeaders(w, cat)
+
+		if _, err := m3uPlaylist(info, movies).WriteTo(w); err != nil {
+			log.Printf("service: failed to write playlist %s: %s", info.id, err)
+		}
+		return
+	}
+
+	channels, err := svc.store.FindAllChannels(r.Context(), cat.Hash)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	self := svc.baseURL(r) + selfURI(r)
+
+	var doc interface{}
+	var contentType string
+	switch format {
+	case feedFormatRSS:
+		doc = rssFeed(cat, info, self, movies, channels)
+		contentType = feed.RSSContentType
+	case feedFormatPodcast:
+		doc = podcastFeed(cat, info, self, svc.baseURL(r)+r.URL.Path, movies,
+			channels)
+		contentType = feed.RSSContentType
+	default:
+		doc = atomFeed(cat, info, self, movies, channels)
+		contentType = feed.AtomContentType
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	setCacheHeaders(w, cat)
+
+	if err := feed.Write(w, doc); err != nil {
+		log.Printf("service: failed to write feed %s: %s", info.id, err)
+	}
+}
+
+func rssFeed(cat catalog.Catalog, info feedInfo, self string, movies []catalog.Movie, channels map[int64]string) *feed.RSS {
+	channel := feed.RSSChannel{
+		Title:         info.title,
+		Link:          self,
+		Description:   info.subtitle,
+		Language:      "de",
+		LastBuildDate: feed.RSSDate(cat.PublishedAt),
+		TTL:           feedTTL,
+	}
+
+	for _, movie := range movies {
+		item := feed.RSSItem{
+			Title:       movie.Title,
+			Link:        movieLink(movie),
+			Description: movie.Descr,
+			Category:    channels[movie.ChannelID],
+			GUID:        feed.RSSGUID{Value: movieGUIDPrefix + movie.StableID},
+		}
+		if !movie.PublishedAt.IsZero() {
+			item.PubDate = feed.RSSDate(movie.PublishedAt)
+		}
+		if streamURL, quality := movie.StreamURL(info.quality); streamURL != "" {
+			item.Enclosure = &feed.RSSEnclosure{
+				URL:    streamURL,
+				Length: streamLength(movie, quality),
+				Type:   streamMediaType(streamURL),
+			}
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+
+	return feed.NewRSS(channel)
+}
+
+func atomFeed(cat catalog.Catalog, info feedInfo, self string, movies []catalog.Movie, channels map[int64]string) *feed.AtomFeed {
+	result := &feed.AtomFeed{
+		ID:       info.id,
+		Title:    info.title,
+		Subtitle: info.subtitle,
+		Updated:  feed.AtomDate(cat.PublishedAt),
+		Author: &feed.AtomPerson{
+			Name: "MediathekView",
+			URI:  "https://mediathekview.de",
+		},
+		Links: []feed.AtomLink{
+			{Href: self, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+
+	for _, movie := range movies {
+		updated := movie.PublishedAt
+		if updated.IsZero() {
+			updated = cat.PublishedAt
+		}
+
+		entry := feed.AtomEntry{
+			ID:      movieGUIDPrefix + movie.StableID,
+			Title:   movie.Title,
+			Updated: feed.AtomDate(updated),
+			Summary: movie.Descr,
+		}
+		if !movie.PublishedAt.IsZero() {
+			entry.Published = feed.AtomDate(movie.PublishedAt)
+		}
+		if name, ok := channels[movie.ChannelID]; ok {
+			entry.Categories = []feed.AtomCategory{{Term: name}}
+		}
+		if link := movieLink(movie); link != "" {
+			entry.Links = append(entry.Links,
+				feed.AtomLink{Href: link, Rel: "alternate"})
+		}
+		if streamURL, quality := movie.StreamURL(info.quality); streamURL != "" {
+			entry.Links = append(entry.Links, feed.AtomLink{
+				Href:   streamURL,
+				Rel:    "enclosure",
+				Type:   streamMediaType(streamURL),
+				Length: streamLength(movie, quality),
+			})
+		}
+
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result
+}
+
+// podcastFeed extends the RSS feed by the iTunes and Podcasting 2.0 tags. The
+// streams in the other qualities are offered as alternate enclosures. The
+// podcast GUID is derived from the feed URL without query parameters, so it
+// stays the same regardless of the chosen quality or limit.
+func podcastFeed(cat catalog.Catalog, info feedInfo, self, feedURL string, movies []catalog.Movie, channels map[int64]string) *feed.RSS {
+	result := rssFeed(cat, info, self, movies, channels)
+	result.EnablePodcast()
+
+	channel := &result.Channel
+	channel.ITunesAuthor = "MediathekView"
+	channel.ITunesSummary = info.subtitle
+	channel.ITunesType = "episodic"
+	channel.ITunesExplicit = "false"
+	channel.ITunesCategory = &feed.ITunesCategory{Text: "TV & Film"}
+	channel.PodcastGUID = feed.PodcastGUID(feedURL)
+	channel.PodcastMedium = "video"
+
+	for i, movie := range movies {
+		item := &channel.Items[i]
+		if movie.Duration > 0 {
+			item.ITunesDuration = feed.ITunesDuration(movie.Duration)
+		}
+		item.ITunesSummary = movie.Descr
+		item.ITunesEpisodeType = "full"
+		item.ITunesExplicit = "false"
+
+		_, selected := movie.StreamURL(info.quality)
+		for _, quality := range []string{catalog.QualityHD, catalog.QualitySD, catalog.QualitySmall} {
+			streamURL, actual := movie.StreamURL(quality)
+			if streamURL == "" || actual != quality {
+				continue
+			}
+			item.AlternateEnclosures = append(item.AlternateEnclosures,
+				feed.PodcastAlternateEnclosure{
+					Type:    streamMediaType(streamURL),
+					Length:  streamLength(movie, quality),
+					Title:   strings.ToUpper(quality),
+					Default: quality == selected,
+					Sources: []feed.PodcastSource{{URI: streamURL}},
+				})
+		}
+	}
+
+	return result
+}
+
+// streamMediaType detects the media type of the stream by the extension of
+// its URL. Most streams are MP4 files, some are HLS playlists.
+func streamMediaType(streamURL string) string {
+	if u, err := url.Parse(streamURL); err == nil {
+		streamURL = u.Path
+	}
+
+	switch strings.ToLower(path.Ext(streamURL)) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".mp3":
+		return "audio/mpeg"
+	case ".webm":
+		return "video/webm"
+	}
+	return "video/mp4"
+}
+
+// streamLength returns the length of the stream in bytes. The movie list only
+// contains the size of the SD stream, the length of the other qualities is
+// unknown.
+func streamLength(movie catalog.Movie, quality string) int64 {
+	if quality != catalog.QualitySD {
+		return 0
+	}
+	return movie.Size * bytesPerMB
+}
+
+// movieLink returns the website of the movie, or the stream if the website is
+// unknown.
+func movieLink(movie catalog.Movie) string {
+	if movie.WebsiteURL != "" {
+		return movie.WebsiteURL
+	}
+	return movie.URL
+}
+
+// baseURL returns the scheme and host the request was sent to. Behind a
+// trusted proxy the forwarded headers are taken into account.
+func (svc *Service) baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+
+	if svc.opts.TrustProxy {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = proto
+		}
+		if fwdHost := r.Header.Get("X-Forwarded-Host"); fwdHost != "" {
+			host = fwdHost
+		}
+	}
+
+	return scheme + "://" + host
+}
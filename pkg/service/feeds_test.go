@@ -0,0 +1,20 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/feed"
+)
+
+func TestPodcastGUIDIgnoresQuery(t *testing.T) {
+	const feedURL = "https://api.example.org/podcasts/topics/42.rss"
+	info := feedInfo{id: "topic-42", title: "Tatort"}
+
+	doc := podcastFeed(catalog.Catalog{}, info, feedURL+"?quality=hd&limit=10",
+		feedURL, nil, nil)
+
+	if got, want := doc.Channel.PodcastGUID, feed.PodcastGUID(feedURL); got != want {
+		t.Errorf("podcast GUID = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcAPIKeyHeader is the metadata key the gRPC clients pass the API key in.
+const grpcAPIKeyHeader = "x-api-key"
+
+// grpcRateClasses maps the full gRPC method names to their rate classes.
+// Methods not listed are searches.
+var grpcRateClasses = map[string]string{
+	"/moviecat.MovieCatalogService/StreamCatalog": rateClassBulk,
+}
+
+// UnaryInterceptor returns a gRPC interceptor authenticating the API key and
+// enforcing the rate limits of unary calls like Auth does for the REST API.
+func (svc *Service) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := svc.authorizeRPC(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns a gRPC interceptor authenticating the API key and
+// enforcing the rate limits of streaming calls like Auth does for the REST
+// API.
+func (svc *Service) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := svc.authorizeRPC(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorizeRPC authorizes the call of the method and maps rejections to the
+// corresponding gRPC status.
+func (svc *Service) authorizeRPC(ctx context.Context, method string) error {
+	class, ok := grpcRateClasses[method]
+	if !ok {
+		class = rateClassSearch
+	}
+
+	var secret string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(grpcAPIKeyHeader); len(values) > 0 {
+			secret = values[0]
+		}
+	}
+
+	rej, err := svc.authorize(secret, peerIP(ctx), class)
+	if err != nil {
+		log.Printf("service: failed to authorize %s: %s", method, err)
+		return status.Error(codes.Internal, "internal error")
+	}
+	if rej == nil {
+		return nil
+	}
+
+	switch rej.status {
+	case http.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, rej.detail)
+	case http.StatusTooManyRequests:
+		return status.Error(codes.ResourceExhausted,
+			fmt.Sprintf("Rate limit exceeded, please retry in %d seconds.",
+				int(math.Ceil(rej.wait.Seconds()))))
+	}
+
+	return status.Error(codes.PermissionDenied, rej.detail)
+}
+
+// peerIP returns the IP of the gRPC client.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return strings.TrimSpace(addr)
+	}
+
+	return host
+}
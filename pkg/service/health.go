@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+// readyTimeout limits the database checks of the readiness probe.
+const readyTimeout = 2 * time.Second
+
+type statusResource struct {
+	Status         string           `json:"status"`
+	Catalog        *catalog.Catalog `json:"catalog,omitempty"`
+	Age            int64            `json:"age,omitempty"`
+	ImportAge      int64            `json:"importAge,omitempty"`
+	ImportInterval int64            `json:"importInterval"`
+	Overdue        int64            `json:"overdue,omitempty"`
+	Stale          bool             `json:"stale"`
+	Error          string           `json:"error,omitempty"`
+	CheckedAt      time.Time        `json:"checkedAt"`
+}
+
+// handleHealthz reports that the process is alive. It doesn't check any
+// dependencies, so a failing database doesn't get the process restarted.
+func (svc *Service) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{"ok"})
+}
+
+// handleReadyz reports if the service is able to serve requests, which is the
+// case if the database is reachable and a current catalog exists.
+func (svc *Service) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	if err := svc.store.Ping(ctx); err != nil {
+		writeProblem(w, http.StatusServiceUnavailable, "Database unreachable.")
+		return
+	}
+
+	if _, err := svc.store.FindCurrentCatalog(ctx); err != nil {
+		detail := "Database query failed."
+		if err == catalog.ErrNoCurrentCatalog {
+			detail = "No movie catalog has been imported yet."
+		}
+		writeProblem(w, http.StatusServiceUnavailable, detail)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{"ready"})
+}
+
+// handleStatus reports the current catalog and its staleness. The catalog is
+// considered stale, if it was published more than one import interval ago,
+// i.e. if at least one scheduled list update has been missed. The ages are
+// given in seconds.
+func (svc *Service) handleStatus(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	resource := statusResource{
+		Status:         "ok",
+		ImportInterval: int64(svc.opts.ImportInterval / time.Second),
+		CheckedAt:      now.UTC(),
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	cat, err := svc.store.FindCurrentCatalog(r.Context())
+	if err == catalog.ErrNoCurrentCatalog {
+		resource.Status = "empty"
+		resource.Stale = true
+		resource.Error = err.Error()
+		writeJSON(w, http.StatusOK, resource)
+		return
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	resource.Catalog = &cat
+	resource.Age = int64(now.Sub(cat.PublishedAt) / time.Second)
+	resource.ImportAge = int64(now.Sub(cat.ImportedAt) / time.Second)
+	if overdue := now.Sub(cat.PublishedAt) - svc.opts.ImportInterval; overdue > 0 {
+		resource.Overdue = int64(overdue / time.Second)
+		resource.Stale = true
+		resource.Status = "stale"
+	}
+
+	writeJSON(w, http.StatusOK, resource)
+}
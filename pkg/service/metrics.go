@@ -0,0 +1,74 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/negroni"
+)
+
+// unmatchedRoute is the route label of requests not matching any route. It
+// keeps arbitrary paths from blowing up the label cardinality.
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "mdthk",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "mdthk",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequests, httpDuration)
+}
+
+// Metrics returns a negroni middleware recording the request count and
+// latency of every request. Requests are labeled with the path template of
+// the matching route, so it must be added in front of the service.
+func (svc *Service) Metrics() negroni.Handler {
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		start := time.Now()
+		next(rw, r)
+
+		status := http.StatusOK
+		if nrw, ok := rw.(negroni.ResponseWriter); ok && nrw.Status() != 0 {
+			status = nrw.Status()
+		}
+
+		labels := prometheus.Labels{
+			"route":  svc.routeTemplate(r),
+			"method": r.Method,
+			"status": strconv.Itoa(status),
+		}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// routeTemplate returns the path template of the route matching the request.
+func (svc *Service) routeTemplate(r *http.Request) string {
+	var match mux.RouteMatch
+	if !svc.r.Match(r, &match) || match.Route == nil {
+		return unmatchedRoute
+	}
+
+	tmpl, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return unmatchedRoute
+	}
+
+	return tmpl
+}
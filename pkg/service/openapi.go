@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/urfave/negroni"
+)
+
+// openAPISpec is the OpenAPI document describing the REST API. It must be
+// kept in sync with the routes and resources of the service.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// mustLoadSpec parses and validates the embedded OpenAPI document and creates
+// a router matching requests against its operations. The document is part of
+// the binary, so errors are programming errors and cause a panic.
+func mustLoadSpec() routers.Router {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		panic("service: invalid OpenAPI document: " + err.Error())
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		panic("service: invalid OpenAPI document: " + err.Error())
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		panic("service: invalid OpenAPI document: " + err.Error())
+	}
+
+	return router
+}
+
+func (svc *Service) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "max-age=3600")
+	w.WriteHeader(http.StatusOK)
+
+	w.Write(openAPISpec)
+}
+
+// Validation returns a negroni middleware validating the requests against
+// the OpenAPI document. Invalid requests are rejected with a problem. Requests
+// not described by the document are passed on, so the router answers them.
+func (svc *Service) Validation() negroni.Handler {
+	opts := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		MultiError:         true,
+	}
+
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		route, pathParams, err := svc.spec.FindRoute(r)
+		if err != nil {
+			next(rw, r)
+			return
+		}
+
+		err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    opts,
+		})
+		if err != nil {
+			writeProblem(rw, http.StatusBadRequest, validationDetail(err))
+			return
+		}
+
+		next(rw, r)
+	})
+}
+
+// validationDetail formats the validation errors as single line.
+func validationDetail(err error) string {
+	var details []string
+
+	if errs, ok := err.(openapi3.MultiError); ok {
+		for _, e := range errs {
+			details = append(details, validationDetail(e))
+		}
+		return strings.Join(details, "; ")
+	}
+
+	if e, ok := err.(*openapi3filter.RequestError); ok && e.Parameter != nil {
+		return "invalid parameter " + e.Parameter.Name + ": " + firstLine(e.Err)
+	}
+
+	return firstLine(err)
+}
+
+func firstLine(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	return msg
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+// strictSchemas rejects properties not described by the object schemas, so
+// fields added to the resources without documenting them are detected.
+func strictSchemas(ref *openapi3.SchemaRef, seen map[*openapi3.Schema]bool) {
+	if ref == nil || ref.Value == nil || seen[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	seen[schema] = true
+
+	if schema.Type.Is(openapi3.TypeObject) && len(schema.Properties) > 0 &&
+		schema.AdditionalProperties.Has == nil &&
+		schema.AdditionalProperties.Schema == nil {
+		schema.AdditionalProperties.Has = openapi3.Ptr(false)
+	}
+
+	for _, prop := range schema.Properties {
+		strictSchemas(prop, seen)
+	}
+	strictSchemas(schema.Items, seen)
+	strictSchemas(schema.AdditionalProperties.Schema, seen)
+}
+
+// responseSchema returns the schema of the response of the operation.
+func responseSchema(t *testing.T, doc *openapi3.T, path string, status int, mime string) *openapi3.Schema {
+	t.Helper()
+
+	item := doc.Paths.Value(path)
+	if item == nil {
+		t.Fatalf("path %s not documented", path)
+	}
+	resp := item.GetOperation(http.MethodGet).Responses.Status(status)
+	if resp == nil || resp.Value.Content.Get(mime) == nil {
+		t.Fatalf("response %d of %s with %s not documented", status, path, mime)
+	}
+
+	return resp.Value.Content.Get(mime).Schema.Value
+}
+
+func testMovie() catalog.Movie {
+	return catalog.Movie{
+		ID:               1,
+		StableID:         "0123456789abcdef0123456789abcdef",
+		GroupID:          "0123456789abcdef0123456789abcdef",
+		ChannelID:        2,
+		TopicID:          3,
+		Topic:            "Tatort",
+		Title:            "Das Team (S01/E02) (Teil 1) - Hörfassung",
+		PublishedAt:      time.Date(2024, 3, 1, 20, 15, 0, 0, time.UTC),
+		Duration:         5400,
+		Size:             800,
+		Descr:            "Krimi",
+		URL:              "https://example.org/movie/960.mp4",
+		WebsiteURL:       "https://example.org/movie",
+		SubTitleURL:      "https://example.org/movie/sub.xml",
+		SmallFormatURL:   "https://example.org/movie/480.mp4",
+		HDFormatURL:      "https://example.org/movie/1280.mp4",
+		HistoryURL:       "https://example.org/movie/history",
+		Geo:              "DE-AT-CH",
+		GeoCodes:         []string{"AT", "CH", "DE"},
+		IsNew:            true,
+		Series:           "Das Team",
+		Season:           1,
+		Episode:          2,
+		Part:             1,
+		AudioDescription: true,
+		SignLanguage:     true,
+		OriginalVersion:  true,
+		BaseID:           "fedcba9876543210fedcba9876543210",
+	}
+}
+
+func TestResourcesMatchOpenAPI(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[*openapi3.Schema]bool)
+	for _, ref := range doc.Components.Schemas {
+		strictSchemas(ref, seen)
+	}
+
+	cat := catalog.Catalog{
+		Hash:          "0123456789abcdef0123456789abcdef",
+		Version:       3,
+		PublishedAt:   time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		ImportedAt:    time.Date(2024, 3, 1, 12, 5, 0, 0, time.UTC),
+		ChannelsCount: 1,
+		TopicsCount:   1,
+		MoviesCount:   1,
+		IsCurrent:     true,
+	}
+
+	movie := movieToResource(testMovie(), true)
+	movie.GeoBlocked = true
+	movie.Alternatives = []movieResource{movieToResource(testMovie(), false)}
+
+	movieList := movieListResource{
+		Meta:     catalogToMetaResource(cat),
+		Channels: map[int64]string{2: "ARD"},
+		Topics:   map[int64]string{3: "Tatort"},
+		Movies:   []movieResource{movie},
+	}
+	movieList.Meta.CompactURLs = true
+	movieList.Meta.Region = "DE"
+
+	changeList := changeListResource{
+		Changes: []changeResource{{
+			Kind:        catalog.ChangeAdded,
+			StableID:    "0123456789abcdef0123456789abcdef",
+			Channel:     "ARD",
+			Topic:       "Tatort",
+			Title:       "Das Team",
+			PublishedAt: 1709320500,
+			Duration:    5400,
+			URL:         "https://example.org/movie/960.mp4",
+			Catalog:     cat.Hash,
+			ChangedAt:   1709320500,
+		}},
+	}
+	changeList.Meta.Since = 1709300000
+	changeList.Meta.Catalog = cat.Hash
+	changeList.Meta.ChangesCount = 1
+
+	status := statusResource{
+		Status:         "stale",
+		Catalog:        &cat,
+		Age:            7200,
+		ImportAge:      6900,
+		ImportInterval: 3600,
+		Overdue:        3300,
+		Stale:          true,
+		Error:          "database unreachable",
+		CheckedAt:      time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC),
+	}
+
+	health := struct {
+		Status string `json:"status"`
+	}{"ok"}
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		mime     string
+		resource interface{}
+	}{
+		{"movie list", "/movies", http.StatusOK, "application/json", movieList},
+		{"changes", "/changes", http.StatusOK, "application/json", changeList},
+		{"status", "/status", http.StatusOK, "application/json", status},
+		{"health", "/healthz", http.StatusOK, "application/json", health},
+		{"problem", "/movies", http.StatusBadRequest, problemContentType, problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusBadRequest),
+			Status: http.StatusBadRequest,
+			Detail: "invalid parameter limit",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := responseSchema(t, doc, tt.path, tt.status, tt.mime)
+
+			data, err := json.Marshal(tt.resource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var value interface{}
+			if err := json.Unmarshal(data, &value); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := schema.VisitJSON(value, openapi3.VisitAsResponse(),
+				openapi3.MultiErrors()); err != nil {
+				t.Errorf("%s doesn't match the schema: %s", data, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// setPaginationLinks adds a Link header pointing to the first, previous, next
+// and last page of the result, see RFC 8288. The links are only set if the
+// result is limited. API keys passed as query parameter aren't repeated in
+// the links.
+func setPaginationLinks(w http.ResponseWriter, r *http.Request, limit, offset, total int) {
+	if limit <= 0 {
+		return
+	}
+
+	var links []string
+	add := func(rel string, offset int) {
+		query := linkQuery(r)
+		query.Set("offset", strconv.Itoa(offset))
+		u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", u.String(), rel))
+	}
+
+	last := 0
+	if total > 0 {
+		last = (total - 1) / limit * limit
+	}
+
+	add("first", 0)
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		add("prev", prev)
+	}
+	if offset+limit < total {
+		add("next", offset+limit)
+	}
+	add("last", last)
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// linkQuery returns the query parameters of the request for links to related
+// resources. API keys passed as query parameter are removed, so they don't
+// leak into the response.
+func linkQuery(r *http.Request) url.Values {
+	query := r.URL.Query()
+	query.Del(apiKeyParam)
+	return query
+}
+
+// selfURI returns the URI of the requested resource for self links without
+// the API key.
+func selfURI(r *http.Request) string {
+	u := url.URL{Path: r.URL.Path, RawQuery: linkQuery(r).Encode()}
+	return u.String()
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLinksOmitAPIKey(t *testing.T) {
+	r := httptest.NewRequest("GET",
+		"/movies?q=tatort&api_key=secret&limit=10&offset=10", nil)
+
+	if got, want := selfURI(r), "/movies?limit=10&offset=10&q=tatort"; got != want {
+		t.Errorf("selfURI() = %s, want %s", got, want)
+	}
+
+	w := httptest.NewRecorder()
+	setPaginationLinks(w, r, 10, 10, 35)
+	link := w.Header().Get("Link")
+	if strings.Contains(link, "secret") || !strings.Contains(link, `offset=20&q=tatort>; rel="next"`) {
+		t.Errorf("Link = %s", link)
+	}
+}
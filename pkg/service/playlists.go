@@ -0,0 +1,54 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/feed"
+)
+
+// handleTopicPlaylist serves the newest movies of a topic as M3U8 playlist.
+// The parameters are the same as for feeds.
+func (svc *Service) handleTopicPlaylist(w http.ResponseWriter, r *http.Request) {
+	svc.handleEntryFeed(w, r, "topic", svc.store.FindTopic,
+		func(f *catalog.MovieFilter, id int64) { f.TopicID = id },
+		feedFormatM3U8)
+}
+
+// handleSearchPlaylist serves the newest movies matching the search query as
+// M3U8 playlist.
+func (svc *Service) handleSearchPlaylist(w http.ResponseWriter, r *http.Request) {
+	svc.handleQueryFeed(w, r, feedFormatM3U8)
+}
+
+// m3uPlaylist creates the playlist of the movies. Each entry points to the
+// stream in the requested quality, movies without stream are skipped.
+func m3uPlaylist(info feedInfo, movies []catalog.Movie) *feed.Playlist {
+	result := &feed.Playlist{Title: info.title}
+
+	for _, movie := range movies {
+		streamURL, _ := movie.StreamURL(info.quality)
+		if streamURL == "" {
+			continue
+		}
+
+		duration := movie.Duration
+		if duration <= 0 {
+			duration = -1
+		}
+
+		title := movie.Title
+		if movie.Topic != "" && !strings.HasPrefix(title, movie.Topic) {
+			title = movie.Topic + " - " + title
+		}
+
+		result.Entries = append(result.Entries, feed.PlaylistEntry{
+			Duration: duration,
+			Title:    title,
+			URL:      streamURL,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// problemContentType is the content type of problem details, see RFC 7807.
+const problemContentType = "application/problem+json"
+
+// problem describes an error response as JSON problem details.
+type problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// writeProblem writes the problem details with the given status. The title is
+// derived from the status.
+func writeProblem(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// writeInternalError logs the error and writes a generic problem. Details of
+// internal errors, e.g. SQL statements, aren't exposed to clients.
+func writeInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("service: %s %s: %s", r.Method, r.URL.Path, err)
+	writeProblem(w, http.StatusInternalServerError,
+		"The request could not be processed, please try again later.")
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/apikey"
+	"github.com/urfave/negroni"
+)
+
+// Rate classes of the routes. Bulk downloads of the whole catalog are a lot
+// more expensive than searches and get a much smaller quota.
+const (
+	rateClassNone   = ""
+	rateClassSearch = "search"
+	rateClassBulk   = "bulk"
+)
+
+// routeRateClasses maps the route templates to their rate classes. Routes not
+// listed are searches.
+var routeRateClasses = map[string]string{
+	"/":             rateClassNone,
+	"/healthz":      rateClassNone,
+	"/readyz":       rateClassNone,
+	"/status":       rateClassNone,
+	"/metrics":      rateClassNone,
+	"/openapi.json": rateClassNone,
+	"/catalog":      rateClassBulk,
+}
+
+// maxSearchLimit is the largest page size of /movies charged as search.
+// Unlimited and larger pages return large parts of the catalog and are charged
+// as bulk download.
+const maxSearchLimit = 1000
+
+// API keys are passed in this header or query parameter
+const (
+	apiKeyHeader = "X-API-Key"
+	apiKeyParam  = "api_key"
+)
+
+// keyCacheTTL is the time API key lookups are cached, which is also the time
+// it takes until a revoked key is rejected.
+const keyCacheTTL = time.Minute
+
+// keyCacheSize is the maximum number of cached API key lookups.
+const keyCacheSize = 10000
+
+// sweepInterval is the interval in which idle buckets are removed.
+const sweepInterval = time.Minute
+
+// Quota configures a token bucket. The rate is given in requests per minute,
+// the burst is the size of the bucket. A negative rate disables the limit.
+type Quota struct {
+	Rate  float64
+	Burst int
+}
+
+// Default quotas of anonymous clients, which are limited per IP, and of
+// clients with an API key
+var (
+	DefaultSearchQuota    = Quota{Rate: 60, Burst: 20}
+	DefaultBulkQuota      = Quota{Rate: 1.0 / 60, Burst: 1}
+	DefaultKeySearchQuota = Quota{Rate: 600, Burst: 100}
+	DefaultKeyBulkQuota   = Quota{Rate: 0.2, Burst: 2}
+)
+
+// withDefault returns the default quota if q isn't set.
+func (q Quota) withDefault(def Quota) Quota {
+	if q.Rate == 0 {
+		return def
+	}
+	if q.Burst < 1 {
+		q.Burst = 1
+	}
+	return q
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	quota   Quota
+}
+
+// refill adds the tokens accumulated since the last update.
+func (b *bucket) refill(now time.Time) {
+	perSecond := b.quota.Rate / 60
+	b.tokens = math.Min(float64(b.quota.Burst),
+		b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+}
+
+// limiter implements token buckets identified by arbitrary IDs.
+type limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newLimiter() *limiter {
+	return &limiter{buckets: make(map[string]*bucket)}
+}
+
+// take removes a token from the bucket with the given ID. If the bucket is
+// empty, the time until the next token is available is returned.
+func (l *limiter) take(id string, q Quota, now time.Time) time.Duration {
+	if q.Rate < 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{tokens: float64(q.Burst), updated: now}
+		l.buckets[id] = b
+	}
+	b.quota = q
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	wait := (1 - b.tokens) / (q.Rate / 60)
+	return time.Duration(wait * float64(time.Second))
+}
+
+// sweep removes all full buckets. They behave exactly like new ones.
+func (l *limiter) sweep(now time.Time) {
+	for id, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.quota.Burst) {
+			delete(l.buckets, id)
+		}
+	}
+	l.lastSweep = now
+}
+
+type cachedKey struct {
+	hash    string
+	key     apikey.Key
+	err     error
+	expires time.Time
+}
+
+// keyCache is a LRU cache of API key lookups. The entries are identified by
+// the SHA-256 hash of the secret, so the secrets aren't kept in memory.
+type keyCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+func newKeyCache(size int) *keyCache {
+	return &keyCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get returns the lookup cached for the hash unless it's expired.
+func (c *keyCache) get(hash string, now time.Time) (cachedKey, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[hash]
+	if !ok {
+		return cachedKey{}, false
+	}
+	entry := elem.Value.(*cachedKey)
+	if now.After(entry.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, hash)
+		return cachedKey{}, false
+	}
+	c.lru.MoveToFront(elem)
+
+	return *entry, true
+}
+
+// put caches the lookup and evicts the least recently used ones exceeding
+// the size of the cache.
+func (c *keyCache) put(entry cachedKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.hash]; ok {
+		c.lru.Remove(elem)
+	}
+	c.entries[entry.hash] = c.lru.PushFront(&entry)
+
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedKey).hash)
+	}
+}
+
+// rejection describes why a request isn't authorized.
+type rejection struct {
+	status int
+	detail string
+	wait   time.Duration
+}
+
+// Auth returns a negroni middleware authenticating the API key and enforcing
+// the rate limits. Clients with a key are limited per key, anonymous clients
+// per IP. Exceeding the quota is answered with 429 and a Retry-After header.
+func (svc *Service) Auth() negroni.Handler {
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		class := svc.rateClass(r)
+		if class == rateClassNone {
+			next(rw, r)
+			return
+		}
+
+		rej, err := svc.authorize(apiKeyFromRequest(r), svc.clientIP(r), class)
+		if err != nil {
+			writeInternalError(rw, r, err)
+			return
+		}
+		if rej != nil {
+			if rej.wait > 0 {
+				rw.Header().Set("Retry-After",
+					strconv.Itoa(int(math.Ceil(rej.wait.Seconds()))))
+			}
+			writeProblem(rw, rej.status, rej.detail)
+			return
+		}
+
+		next(rw, r)
+	})
+}
+
+// rateClass returns the rate class of the request.
+func (svc *Service) rateClass(r *http.Request) string {
+	template := svc.routeTemplate(r)
+	class, ok := routeRateClasses[template]
+	if !ok {
+		class = rateClassSearch
+	}
+
+	// Invalid limits are rejected by the handler and charged as search
+	if template == "/movies" {
+		limit, err := parseIntParam(r.URL.Query(), "limit")
+		if err == nil && (limit == 0 || limit > maxSearchLimit) {
+			class = rateClassBulk
+		}
+	}
+
+	return class
+}
+
+// authorize authenticates the API key and takes a token of the rate class
+// from the bucket of the client. It's shared by the REST and gRPC services.
+// Keys not known to be valid are charged to the IP of the client before they
+// are looked up, so guessing keys is limited like anonymous requests.
+func (svc *Service) authorize(secret, ip, class string) (*rejection, error) {
+	ipID := "ip:" + ip + ":" + class
+	ipQuota := svc.opts.SearchQuota
+	if class == rateClassBulk {
+		ipQuota = svc.opts.BulkQuota
+	}
+
+	if secret == "" {
+		if svc.opts.RequireAPIKey {
+			return &rejection{status: http.StatusUnauthorized,
+				detail: "An API key is required."}, nil
+		}
+		return svc.take(ipID, ipQuota), nil
+	}
+
+	now := time.Now()
+	hash := hashSecret(secret)
+	cached, ok := svc.keyCache.get(hash, now)
+	if !ok || cached.err != nil {
+		if rej := svc.take(ipID, ipQuota); rej != nil {
+			return rej, nil
+		}
+	}
+	if !ok {
+		var err error
+		cached, err = svc.lookupKey(hash, secret, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cached.err == apikey.ErrKeyNotFound {
+		return &rejection{status: http.StatusUnauthorized,
+			detail: "Invalid API key."}, nil
+	}
+
+	quota := svc.opts.KeySearchQuota
+	rate := cached.key.SearchRate
+	if class == rateClassBulk {
+		quota = svc.opts.KeyBulkQuota
+		rate = cached.key.BulkRate
+	}
+	if rate != 0 {
+		quota.Rate = rate
+	}
+
+	return svc.take("key:"+strconv.FormatInt(cached.key.ID, 10)+":"+class, quota), nil
+}
+
+// take removes a token from the bucket and rejects the request if it's empty.
+func (svc *Service) take(id string, quota Quota) *rejection {
+	wait := svc.limiter.take(id, quota, time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	return &rejection{status: http.StatusTooManyRequests,
+		detail: "Rate limit exceeded, please retry later.", wait: wait}
+}
+
+// lookupKey looks up the API key with the given secret. Results including
+// unknown keys are cached to spare the database.
+func (svc *Service) lookupKey(hash, secret string, now time.Time) (cachedKey, error) {
+	key, err := svc.keys.Lookup(secret)
+	if err != nil && err != apikey.ErrKeyNotFound {
+		return cachedKey{}, err
+	}
+
+	cached := cachedKey{hash: hash, key: key, err: err, expires: now.Add(keyCacheTTL)}
+	svc.keyCache.put(cached)
+
+	return cached, nil
+}
+
+// hashSecret returns the hex encoded SHA-256 hash of the API key.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	return r.URL.Query().Get(apiKeyParam)
+}
+
+// clientIP returns the IP of the client. Behind a trusted proxy the last
+// entry of the X-Forwarded-For header is used, which is the one appended by
+// the proxy itself and can't be spoofed by the client.
+func (svc *Service) clientIP(r *http.Request) string {
+	if svc.opts.TrustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			parts := strings.Split(xff, ",")
+			return strings.TrimSpace(parts[len(parts)-1])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/apikey"
+)
+
+func TestKeyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newKeyCache(2)
+	now := time.Now()
+
+	c.put(cachedKey{hash: "a", expires: now.Add(time.Minute)})
+	c.put(cachedKey{hash: "b", expires: now.Add(time.Minute)})
+	if _, ok := c.get("a", now); !ok {
+		t.Fatal("a not cached")
+	}
+	c.put(cachedKey{hash: "c", expires: now.Add(time.Minute)})
+
+	if _, ok := c.get("b", now); ok {
+		t.Error("b not evicted")
+	}
+	if _, ok := c.get("a", now); !ok {
+		t.Error("a evicted")
+	}
+	if _, ok := c.get("c", now.Add(2*time.Minute)); ok {
+		t.Error("expired c returned")
+	}
+}
+
+func TestAuthorizeLimitsUnknownKeysPerIP(t *testing.T) {
+	svc := &Service{
+		opts: Options{
+			SearchQuota:    Quota{Rate: 1, Burst: 1},
+			KeySearchQuota: DefaultKeySearchQuota,
+		},
+		limiter:  newLimiter(),
+		keyCache: newKeyCache(keyCacheSize),
+	}
+	svc.keyCache.put(cachedKey{hash: hashSecret("invalid"),
+		err: apikey.ErrKeyNotFound, expires: time.Now().Add(time.Minute)})
+
+	rej, err := svc.authorize("invalid", "192.0.2.1", rateClassSearch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rej == nil || rej.status != http.StatusUnauthorized {
+		t.Fatalf("rejection = %+v, want 401", rej)
+	}
+
+	// The bucket of the IP is empty now, so further keys are rejected before
+	// they are looked up, which would fail without key store
+	rej, err = svc.authorize("guessed", "192.0.2.1", rateClassSearch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rej == nil || rej.status != http.StatusTooManyRequests {
+		t.Fatalf("rejection = %+v, want 429", rej)
+	}
+}
+
+func TestRateClassOfMovieSearches(t *testing.T) {
+	svc := New(nil, nil, Options{})
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"/movies", rateClassBulk},
+		{"/movies?limit=0", rateClassBulk},
+		{"/movies?limit=20", rateClassSearch},
+		{"/movies?limit=1000", rateClassSearch},
+		{"/movies?limit=1001", rateClassBulk},
+		{"/movies?limit=invalid", rateClassSearch},
+		{"/catalog", rateClassBulk},
+		{"/healthz", rateClassNone},
+		{"/changes", rateClassSearch},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if got := svc.rateClass(r); got != tt.want {
+			t.Errorf("rateClass(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
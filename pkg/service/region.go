@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+// Handling of geo-blocked movies given by the geoblocked parameter
+const (
+	geoBlockedHide = "hide"
+	geoBlockedFlag = "flag"
+)
+
+// CountryResolver resolves the country of a client IP address. It's
+// implemented by geoip.DB.
+type CountryResolver interface {
+	Country(ip string) string
+}
+
+// clientRegion determines the country of the client. The region parameter
+// takes precedence over the GeoIP lookup. Derived is true if the region was
+// looked up, which makes the response depend on the client address. An empty
+// region means the region is unknown.
+func (svc *Service) clientRegion(r *http.Request) (region string, derived bool, err error) {
+	if param := r.URL.Query().Get("region"); param != "" {
+		region, ok := catalog.NormalizeRegion(param)
+		if !ok {
+			return "", false, fmt.Errorf("invalid region %q", param)
+		}
+		return region, false, nil
+	}
+
+	if svc.opts.GeoIP == nil {
+		return "", false, nil
+	}
+
+	region, ok := catalog.NormalizeRegion(svc.opts.GeoIP.Country(svc.clientIP(r)))
+	if !ok {
+		return "", false, nil
+	}
+
+	return region, true, nil
+}
+
+// requestRegion determines the region of the client for the response. On
+// error a problem is written and false is returned.
+func (svc *Service) requestRegion(w http.ResponseWriter, r *http.Request) (string, bool) {
+	region, derived, err := svc.clientRegion(r)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+
+	// Shared caches must not serve the response to clients of other regions
+	if derived {
+		w.Header().Add("Vary", "*")
+	}
+
+	return region, true
+}
+
+// applyRegion restricts the filter to the movies available in the client's
+// region, unless the client asked to flag geo-blocked movies instead. The
+// region is returned for flagging. On error a problem is written and false is
+// returned.
+func (svc *Service) applyRegion(w http.ResponseWriter, r *http.Request, filter *catalog.MovieFilter) (string, bool) {
+	mode := r.URL.Query().Get("geoblocked")
+	if mode != "" && mode != geoBlockedHide && mode != geoBlockedFlag {
+		writeProblem(w, http.StatusBadRequest,
+			fmt.Sprintf("invalid value %q for parameter geoblocked", mode))
+		return "", false
+	}
+
+	region, ok := svc.requestRegion(w, r)
+	if !ok {
+		return "", false
+	}
+
+	if mode != geoBlockedFlag {
+		filter.Region = region
+	}
+
+	return region, true
+}
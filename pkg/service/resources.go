@@ -0,0 +1,124 @@
+package service
+
+import (
+	"strconv"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	pb "github.com/tschokko/mdthk-api/pkg/moviecat"
+)
+
+type movieMetaResource struct {
+	PublishedAt   int64  `json:"publishedAt"`
+	Version       int    `json:"version"`
+	MD5Hash       string `json:"hash"`
+	ChannelsCount int    `json:"channelsCount"`
+	TopicsCount   int    `json:"topicsCount"`
+	MoviesCount   int    `json:"moviesCount"`
+	CompactURLs   bool   `json:"compactUrls,omitempty"`
+	Region        string `json:"region,omitempty"`
+}
+
+type movieResource struct {
+	Slug              string `json:"id"`
+	GroupID           string `json:"gr,omitempty"`
+	ChannelID         int64  `json:"ch,omitempty"`
+	TopicID           int64  `json:"tp,omitempty"`
+	Title             string `json:"ti,omitempty"`
+	PublishedAt       int64  `json:"ts,omitempty"`
+	Duration          int64  `json:"dr,omitempty"`
+	Size              int64  `json:"sz,omitempty"`
+	Descr             string `json:"ds,omitempty"`
+	HasWebsiteURL     bool   `json:"ws,omitempty"`
+	HasSubTitleURL    bool   `json:"st,omitempty"`
+	HasSmallFormatURL bool   `json:"sm,omitempty"`
+	HasHDFormatURL    bool   `json:"hd,omitempty"`
+	HasHistoryURL     bool   `json:"hi,omitempty"`
+	Geo               string `json:"ge,omitempty"`
+	GeoBlocked        bool   `json:"gb,omitempty"`
+	IsNew             bool   `json:"ne,omitempty"`
+	Series            string `json:"sr,omitempty"`
+	Season            int    `json:"sn,omitempty"`
+	Episode           int    `json:"ep,omitempty"`
+	Part              int    `json:"pt,omitempty"`
+	AudioDescription  bool   `json:"ad,omitempty"`
+	SignLanguage      bool   `json:"sl,omitempty"`
+	OriginalVersion   bool   `json:"ov,omitempty"`
+	BaseID            string `json:"bs,omitempty"`
+	URL               string `json:"ur,omitempty"`
+	SubTitleURL       string `json:"stu,omitempty"`
+	SmallFormatURL    string `json:"smu,omitempty"`
+	HDFormatURL       string `json:"hdu,omitempty"`
+	HistoryURL        string `json:"hiu,omitempty"`
+
+	Alternatives []movieResource `json:"alt,omitempty"`
+}
+
+type movieListResource struct {
+	Meta     movieMetaResource `json:"meta"`
+	Channels map[int64]string  `json:"channels,omitempty"`
+	Topics   map[int64]string  `json:"topics,omitempty"`
+	Movies   []movieResource   `json:"movies,omitempty"`
+}
+
+func catalogToMetaResource(cat catalog.Catalog) movieMetaResource {
+	return movieMetaResource{
+		PublishedAt:   cat.PublishedAt.Unix(),
+		Version:       cat.Version,
+		MD5Hash:       cat.Hash,
+		ChannelsCount: cat.ChannelsCount,
+		TopicsCount:   cat.TopicsCount,
+		MoviesCount:   cat.MoviesCount,
+	}
+}
+
+func movieToResource(movie catalog.Movie, compactURLs bool) movieResource {
+	var result movieResource
+
+	result.Slug = strconv.FormatInt(movie.ID, 10)
+	result.GroupID = movie.GroupID
+	result.ChannelID = movie.ChannelID
+	result.TopicID = movie.TopicID
+	result.Title = movie.Title
+	result.PublishedAt = movie.PublishedAt.Unix()
+	result.Duration = movie.Duration
+	result.Size = movie.Size
+	result.Descr = movie.Descr
+	if movie.WebsiteURL != "" {
+		result.HasWebsiteURL = true
+	}
+	if movie.SubTitleURL != "" {
+		result.HasSubTitleURL = true
+	}
+	if movie.SmallFormatURL != "" {
+		result.HasSmallFormatURL = true
+	}
+	if movie.HDFormatURL != "" {
+		result.HasHDFormatURL = true
+	}
+	if movie.HistoryURL != "" {
+		result.HasHistoryURL = true
+	}
+	result.Geo = movie.Geo
+	result.IsNew = movie.IsNew
+	result.Series = movie.Series
+	result.Season = movie.Season
+	result.Episode = movie.Episode
+	result.Part = movie.Part
+	result.AudioDescription = movie.AudioDescription
+	result.SignLanguage = movie.SignLanguage
+	result.OriginalVersion = movie.OriginalVersion
+	result.BaseID = movie.BaseID
+	result.URL = movie.URL
+	result.SubTitleURL = movie.SubTitleURL
+	result.SmallFormatURL = movie.SmallFormatURL
+	result.HDFormatURL = movie.HDFormatURL
+	result.HistoryURL = movie.HistoryURL
+	if compactURLs {
+		result.SubTitleURL = pb.CompactURL(movie.URL, movie.SubTitleURL)
+		result.SmallFormatURL = pb.CompactURL(movie.URL, movie.SmallFormatURL)
+		result.HDFormatURL = pb.CompactURL(movie.URL, movie.HDFormatURL)
+		result.HistoryURL = pb.CompactURL(movie.URL, movie.HistoryURL)
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/feed"
+	"github.com/tschokko/mdthk-api/pkg/savedsearch"
+)
+
+// handleSavedSearchFeed serves the matches of a saved search as Atom feed.
+// The feed is identified by the token of the search, so its URL can be
+// handed out without revealing the search. The matches are kept across
+// catalogs, so the movies of the feed may no longer be available.
+func (svc *Service) handleSavedSearchFeed(w http.ResponseWriter, r *http.Request) {
+	if svc.opts.SavedSearches == nil {
+		writeProblem(w, http.StatusNotFound, "")
+		return
+	}
+
+	search, err := svc.opts.SavedSearches.FindByFeedToken(mux.Vars(r)["token"])
+	if err == savedsearch.ErrSearchNotFound {
+		writeProblem(w, http.StatusNotFound, "The saved search doesn't exist.")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	info, ok := svc.parseFeedInfo(w, r)
+	if !ok {
+		return
+	}
+	info.id = "urn:mdthk:saved-search:" + search.FeedToken
+	info.title = fmt.Sprintf("Gespeicherte Suche: %s", search.Name)
+	info.subtitle = fmt.Sprintf("Neue Treffer der Suche %q", search.Name)
+
+	matches, err := svc.opts.SavedSearches.FindMatches(search.ID, info.filter.Limit)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	// The matches only change after a catalog switch, the newest one
+	// identifies the state of the feed.
+	etag := strconv.Quote("empty")
+	if len(matches) > 0 {
+		etag = strconv.Quote(fmt.Sprintf("%s-%d", matches[0].CatalogHash,
+			matches[0].MatchedAt.Unix()))
+	}
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	cat, ok := svc.currentCatalog(w, r)
+	if !ok {
+		return
+	}
+
+	// Feeds can't flag movies, so geo-blocked movies are always hidden
+	var movies []catalog.Movie
+	for _, match := range matches {
+		if match.Movie.AvailableIn(info.filter.Region) {
+			movies = append(movies, match.Movie)
+		}
+	}
+
+	channels, err := svc.store.FindAllChannels(r.Context(), cat.Hash)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	doc := atomFeed(cat, info, svc.baseURL(r)+selfURI(r), movies, channels)
+	if len(matches) > 0 {
+		doc.Updated = feed.AtomDate(matches[0].MatchedAt)
+	}
+
+	w.Header().Set("Content-Type", feed.AtomContentType)
+	w.Header().Set("Etag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", cacheMaxAge))
+
+	if err := feed.Write(w, doc); err != nil {
+		log.Printf("service: failed to write feed %s: %s", info.id, err)
+	}
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/tschokko/mdthk-api/pkg/apikey"
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	pb "github.com/tschokko/mdthk-api/pkg/moviecat"
+	"github.com/tschokko/mdthk-api/pkg/savedsearch"
+	"github.com/tschokko/mdthk-api/pkg/subtitle"
+)
+
+// cacheMaxAge is the max age of cacheable responses in seconds, 2 hours
+const cacheMaxAge = 7200
+
+// DefaultImportInterval is the default interval in which new catalogs are
+// expected to be imported.
+const DefaultImportInterval = time.Hour
+
+// Options configures the REST service. Zero values select the defaults.
+type Options struct {
+	// ImportInterval is the interval in which the importer daemon polls for
+	// new movie lists. It's used to report the staleness of the catalog.
+	ImportInterval time.Duration
+
+	// RequireAPIKey rejects requests without API key. Otherwise anonymous
+	// clients are allowed and limited per IP.
+	RequireAPIKey bool
+
+	// TrustProxy takes the client IP from the X-Forwarded-For header. It must
+	// only be set behind a reverse proxy.
+	TrustProxy bool
+
+	// Quotas of anonymous clients and clients with an API key. The rates of
+	// an API key stored in the database take precedence.
+	SearchQuota    Quota
+	BulkQuota      Quota
+	KeySearchQuota Quota
+	KeyBulkQuota   Quota
+
+	// CORS configures the cross-origin resource sharing.
+	CORS CORSOptions
+
+	// GeoIP resolves the region of clients which don't pass the region
+	// parameter. Without it geo-blocked movies are only hidden on request.
+	GeoIP CountryResolver
+
+	// SubtitleClient downloads the subtitle files from the broadcasters. It
+	// defaults to a client with a timeout of 10 seconds.
+	SubtitleClient subtitle.HTTPClient
+
+	// SavedSearches provides the matches of the saved searches for their
+	// feeds. Without it the feeds of saved searches aren't found.
+	SavedSearches *savedsearch.Store
+}
+
+// Service implements the REST API on top of the catalog store.
+type Service struct {
+	r       *mux.Router
+	spec    routers.Router
+	store   *catalog.Store
+	keys    *apikey.Store
+	opts    Options
+	limiter *limiter
+
+	subtitles *subtitle.Fetcher
+	keyCache  *keyCache
+}
+
+// New creates a new REST service instance for the given stores.
+func New(store *catalog.Store, keys *apikey.Store, opts Options) *Service {
+	if opts.ImportInterval <= 0 {
+		opts.ImportInterval = DefaultImportInterval
+	}
+	opts.SearchQuota = opts.SearchQuota.withDefault(DefaultSearchQuota)
+	opts.BulkQuota = opts.BulkQuota.withDefault(DefaultBulkQuota)
+	opts.KeySearchQuota = opts.KeySearchQuota.withDefault(DefaultKeySearchQuota)
+	opts.KeyBulkQuota = opts.KeyBulkQuota.withDefault(DefaultKeyBulkQuota)
+	opts.CORS = opts.CORS.withDefaults()
+	if opts.SubtitleClient == nil {
+		opts.SubtitleClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	svc := &Service{
+		r:         mux.NewRouter(),
+		spec:      mustLoadSpec(),
+		store:     store,
+		keys:      keys,
+		opts:      opts,
+		limiter:   newLimiter(),
+		keyCache:  newKeyCache(keyCacheSize),
+		subtitles: subtitle.NewFetcher(opts.SubtitleClient, 0, 0),
+	}
+	svc.setupHandleFuncs()
+	return svc
+}
+
+// ServeHTTP dispatches the request to the handler of the matching route.
+func (svc *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	svc.r.ServeHTTP(w, r)
+}
+
+func (svc *Service) setupHandleFuncs() {
+	svc.r.HandleFunc("/", svc.handleIndex).Methods("GET")
+	svc.r.HandleFunc("/movies", svc.handleMovies).Methods("GET")
+	svc.r.HandleFunc("/movies/{id:[0-9a-fA-F]+}/subtitles.{format:vtt|srt}", svc.handleSubtitles).Methods("GET")
+	svc.r.HandleFunc("/catalog", svc.handleCatalog).Methods("GET")
+	svc.r.HandleFunc("/changes", svc.handleChanges).Methods("GET")
+	svc.r.HandleFunc("/feeds/topics/{id:[0-9]+}.{format:atom|rss}", svc.handleTopicFeed).Methods("GET")
+	svc.r.HandleFunc("/feeds/channels/{id:[0-9]+}.{format:atom|rss}", svc.handleChannelFeed).Methods("GET")
+	svc.r.HandleFunc("/feeds/search.{format:atom|rss}", svc.handleSearchFeed).Methods("GET")
+	svc.r.HandleFunc("/feeds/searches/{token:[0-9a-f]{32}}.atom", svc.handleSavedSearchFeed).Methods("GET")
+	svc.r.HandleFunc("/podcasts/topics/{id:[0-9]+}.rss", svc.handleTopicPodcast).Methods("GET")
+	svc.r.HandleFunc("/playlists/topics/{id:[0-9]+}.m3u8", svc.handleTopicPlaylist).Methods("GET")
+	svc.r.HandleFunc("/playlists/search.m3u8", svc.handleSearchPlaylist).Methods("GET")
+	svc.r.HandleFunc("/healthz", svc.handleHealthz).Methods("GET")
+	svc.r.HandleFunc("/readyz", svc.handleReadyz).Methods("GET")
+	svc.r.HandleFunc("/status", svc.handleStatus).Methods("GET")
+	svc.r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	svc.r.HandleFunc("/openapi.json", svc.handleOpenAPI).Methods("GET")
+
+	svc.r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, http.StatusNotFound, "")
+	})
+	svc.r.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, http.StatusMethodNotAllowed, "")
+	})
+}
+
+func (svc *Service) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "OK")
+}
+
+func (svc *Service) handleMovies(w http.ResponseWriter, r *http.Request) {
+	var resource movieListResource
+
+	queryParams := r.URL.Query()
+	filter, err := parseMovieFilter(queryParams)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	region, ok := svc.applyRegion(w, r, &filter)
+	if !ok {
+		return
+	}
+
+	cat, ok := svc.currentCatalog(w, r)
+	if !ok {
+		return
+	}
+
+	if notModified(w, r, cat) {
+		return
+	}
+
+	// Populate meta
+	resource.Meta = catalogToMetaResource(cat)
+	resource.Meta.Region = region
+	resource.Meta.MoviesCount, err = svc.store.CountMovies(r.Context(), cat.Hash, filter)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	// Populate channels if nochannels not set
+	if _, ok := queryParams["nochannels"]; !ok {
+		resource.Channels, err = svc.store.FindAllChannels(r.Context(), cat.Hash)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+
+	// Populate topics if notopics not set
+	if _, ok := queryParams["notopics"]; !ok {
+		resource.Topics, err = svc.store.FindAllTopics(r.Context(), cat.Hash)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+
+	// Compact the secondary URLs of each movie if compact is set
+	_, resource.Meta.CompactURLs = queryParams["compact"]
+
+	// Populate movies if nomovies not set
+	if _, ok := queryParams["nomovies"]; !ok {
+		movies, err := svc.store.FindMovies(r.Context(), cat.Hash, filter)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		// Attach the other broadcasts of each group to its representative
+		var alternatives map[string][]catalog.Movie
+		if filter.Collapse {
+			alternatives, err = svc.store.FindGroupAlternatives(r.Context(), cat.Hash, movies)
+			if err != nil {
+				writeInternalError(w, r, err)
+				return
+			}
+		}
+
+		for _, movie := range movies {
+			res := movieToResource(movie, resource.Meta.CompactURLs)
+			res.GeoBlocked = !movie.AvailableIn(region)
+			for _, alt := range alternatives[movie.GroupID] {
+				altRes := movieToResource(alt, resource.Meta.CompactURLs)
+				altRes.GeoBlocked = !alt.AvailableIn(region)
+				res.Alternatives = append(res.Alternatives, altRes)
+			}
+			resource.Movies = append(resource.Movies, res)
+		}
+	}
+
+	setPaginationLinks(w, r, filter.Limit, filter.Offset,
+		resource.Meta.MoviesCount)
+
+	w.Header().Set("Content-Type", "application/json")
+	setCacheHeaders(w, cat)
+
+	json.NewEncoder(w).Encode(resource)
+}
+
+// handleCatalog streams the current catalog as length-delimited protobuf
+// messages. See moviecat.Encoder for the format.
+func (svc *Service) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	cat, ok := svc.currentCatalog(w, r)
+	if !ok {
+		return
+	}
+
+	if notModified(w, r, cat) {
+		return
+	}
+
+	var opts catalog.ExportOptions
+	_, opts.CompactURLs = r.URL.Query()["compact"]
+
+	w.Header().Set("Content-Type", pb.StreamContentType)
+	setCacheHeaders(w, cat)
+
+	// Once streaming started, errors can't be reported to the client anymore
+	err := svc.store.ExportCatalog(r.Context(), cat, pb.NewEncoder(w), opts)
+	if err != nil {
+		log.Printf("service: failed to stream catalog %s: %s", cat.Hash, err)
+	}
+}
+
+// notModified handles conditional requests. The responses only change with a
+// new catalog, so the quoted catalog hash serves as E-Tag. If the client's copy
+// is up to date, 304 is written and true is returned.
+func notModified(w http.ResponseWriter, r *http.Request, cat catalog.Catalog) bool {
+	match := r.Header.Get("If-None-Match")
+	if match == "" || !strings.Contains(match, strconv.Quote(cat.Hash)) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// setCacheHeaders sets the E-Tag and the caching of responses depending on the
+// current catalog.
+func setCacheHeaders(w http.ResponseWriter, cat catalog.Catalog) {
+	w.Header().Set("Etag", strconv.Quote(cat.Hash))
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", cacheMaxAge))
+}
+
+// writeJSON writes v as JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(v)
+}
+
+// currentCatalog fetches the current catalog. On error a problem is written
+// and false is returned.
+func (svc *Service) currentCatalog(w http.ResponseWriter, r *http.Request) (catalog.Catalog, bool) {
+	cat, err := svc.store.FindCurrentCatalog(r.Context())
+	if err == catalog.ErrNoCurrentCatalog {
+		writeProblem(w, http.StatusServiceUnavailable,
+			"No movie catalog has been imported yet.")
+		return cat, false
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return cat, false
+	}
+
+	return cat, true
+}
+
+// parseMovieFilter builds the movie filter from the query parameters.
+func parseMovieFilter(queryParams url.Values) (catalog.MovieFilter, error) {
+	var filter catalog.MovieFilter
+	var err error
+
+	filter.Query = queryParams.Get("q")
+	filter.Series = queryParams.Get("series")
+	filter.BaseID = queryParams.Get("base")
+
+	filter.Sort = queryParams.Get("sort")
+	if !catalog.ValidSort(filter.Sort) {
+		return filter, fmt.Errorf("invalid sort order %q", filter.Sort)
+	}
+
+	intParams := []struct {
+		name string
+		val  *int64
+	}{
+		{"channel", &filter.ChannelID},
+		{"topic", &filter.TopicID},
+		{"minDuration", &filter.MinDuration},
+		{"maxDuration", &filter.MaxDuration},
+		{"season", &filter.Season},
+		{"episode", &filter.Episode},
+	}
+	for _, p := range intParams {
+		*p.val, err = parseIntParam(queryParams, p.name)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	boolParams := []struct {
+		name string
+		val  **bool
+	}{
+		{"audioDescription", &filter.AudioDescription},
+		{"signLanguage", &filter.SignLanguage},
+		{"originalVersion", &filter.OriginalVersion},
+	}
+	for _, p := range boolParams {
+		*p.val, err = parseBoolParam(queryParams, p.name)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if val := queryParams.Get("collapse"); val != "" {
+		filter.Collapse, err = strconv.ParseBool(val)
+		if err != nil {
+			return filter, fmt.Errorf("invalid value %q for parameter collapse", val)
+		}
+	}
+
+	limit, err := parseIntParam(queryParams, "limit")
+	if err != nil {
+		return filter, err
+	}
+	filter.Limit = int(limit)
+
+	offset, err := parseIntParam(queryParams, "offset")
+	if err != nil {
+		return filter, err
+	}
+	filter.Offset = int(offset)
+
+	return filter, nil
+}
+
+// parseIntParam parses an optional non-negative integer query parameter. If
+// the parameter isn't set, zero is returned.
+func parseIntParam(queryParams url.Values, name string) (int64, error) {
+	val := queryParams.Get(name)
+	if val == "" {
+		return 0, nil
+	}
+
+	result, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || result < 0 {
+		return 0, fmt.Errorf("invalid value %q for parameter %s", val, name)
+	}
+
+	return result, nil
+}
+
+// parseBoolParam parses an optional boolean query parameter. If the parameter
+// isn't set, nil is returned.
+func parseBoolParam(queryParams url.Values, name string) (*bool, error) {
+	val := queryParams.Get(name)
+	if val == "" {
+		return nil, nil
+	}
+
+	result, err := strconv.ParseBool(val)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q for parameter %s", val, name)
+	}
+
+	return &result, nil
+}
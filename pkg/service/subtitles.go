@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+	"github.com/tschokko/mdthk-api/pkg/subtitle"
+)
+
+// Subtitle formats served by the proxy
+const (
+	subtitleFormatVTT = "vtt"
+	subtitleFormatSRT = "srt"
+)
+
+// handleSubtitles serves the subtitles of a movie converted to WebVTT or
+// SubRip. The movie is addressed by its ID or its stable ID.
+func (svc *Service) handleSubtitles(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	cat, ok := svc.currentCatalog(w, r)
+	if !ok {
+		return
+	}
+
+	movie, err := svc.store.FindMovie(r.Context(), cat.Hash, vars["id"])
+	if err == catalog.ErrMovieNotFound {
+		writeProblem(w, http.StatusNotFound, "The movie doesn't exist.")
+		return
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	if movie.SubTitleURL == "" {
+		writeProblem(w, http.StatusNotFound, "The movie has no subtitles.")
+		return
+	}
+
+	if notModified(w, r, cat) {
+		return
+	}
+
+	cues, err := svc.subtitles.Fetch(r.Context(), movie.SubTitleURL)
+	if err != nil {
+		log.Printf("service: failed to fetch subtitles of movie %d: %s",
+			movie.ID, err)
+		writeProblem(w, http.StatusBadGateway,
+			"The subtitles couldn't be fetched from the broadcaster.")
+		return
+	}
+
+	// Write into a buffer, so a failure still results in a proper problem
+	var buf bytes.Buffer
+	contentType := subtitle.VTTContentType
+	if vars["format"] == subtitleFormatSRT {
+		contentType = subtitle.SRTContentType
+		err = subtitle.WriteSRT(&buf, cues)
+	} else {
+		err = subtitle.WriteVTT(&buf, cues)
+	}
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	setCacheHeaders(w, cat)
+
+	buf.WriteTo(w)
+}
@@ -0,0 +1,154 @@
+package subtitle
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxSubtitleSize limits the size of downloaded subtitle files. Subtitles of
+// long movies are a few hundred kilobytes.
+const maxSubtitleSize = 5 << 20
+
+// Defaults of the fetcher cache
+const (
+	DefaultCacheSize = 256
+	DefaultCacheTTL  = 6 * time.Hour
+)
+
+// HTTPClient is the interface of the client used to download the subtitles.
+// It's satisfied by *http.Client and allows to plug in custom transports.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Fetcher downloads and parses subtitle files. The parsed cues are kept in a
+// least recently used cache, since the files don't change once published.
+type Fetcher struct {
+	client HTTPClient
+	size   int
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	url       string
+	cues      []Cue
+	fetchedAt time.Time
+}
+
+// NewFetcher creates a fetcher using the given client. At most size parsed
+// files are cached for the given time to live. Zero values select the
+// defaults.
+func NewFetcher(client HTTPClient, size int, ttl time.Duration) *Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &Fetcher{
+		client:  client,
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Fetch returns the cues of the subtitle file at the given URL.
+func (f *Fetcher) Fetch(ctx context.Context, url string) ([]Cue, error) {
+	if cues, ok := f.cached(url); ok {
+		return cues, nil
+	}
+
+	cues, err := f.download(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	f.store(url, cues)
+
+	return cues, nil
+}
+
+func (f *Fetcher) download(ctx context.Context, url string) ([]Cue, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSubtitleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSubtitleSize {
+		return nil, fmt.Errorf("fetching %s: subtitle file too large", url)
+	}
+
+	cues, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", url, err)
+	}
+
+	return cues, nil
+}
+
+func (f *Fetcher) cached(url string) ([]Cue, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	elem, ok := f.entries[url]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Since(entry.fetchedAt) > f.ttl {
+		f.lru.Remove(elem)
+		delete(f.entries, url)
+		return nil, false
+	}
+
+	f.lru.MoveToFront(elem)
+	return entry.cues, true
+}
+
+func (f *Fetcher) store(url string, cues []Cue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if elem, ok := f.entries[url]; ok {
+		f.lru.Remove(elem)
+	}
+
+	f.entries[url] = f.lru.PushFront(&cacheEntry{url: url, cues: cues,
+		fetchedAt: time.Now()})
+
+	for f.lru.Len() > f.size {
+		oldest := f.lru.Back()
+		f.lru.Remove(oldest)
+		delete(f.entries, oldest.Value.(*cacheEntry).url)
+	}
+}
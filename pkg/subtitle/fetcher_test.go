@@ -0,0 +1,74 @@
+package subtitle
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testVTT = "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHallo\n"
+
+// fakeClient serves testVTT for every URL and counts the requests.
+type fakeClient struct {
+	requests map[string]int
+}
+
+func (c *fakeClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests[req.URL.String()]++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Body:       io.NopCloser(strings.NewReader(testVTT)),
+	}, nil
+}
+
+func TestFetcherCachesCues(t *testing.T) {
+	client := &fakeClient{requests: make(map[string]int)}
+	f := NewFetcher(client, 1, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		cues, err := f.Fetch(ctx, "https://example.org/a.vtt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cues) != 1 || cues[0].Text != "Hallo" {
+			t.Fatalf("cues = %v, want one cue Hallo", cues)
+		}
+	}
+	if n := client.requests["https://example.org/a.vtt"]; n != 1 {
+		t.Errorf("a.vtt fetched %d times, want 1", n)
+	}
+
+	// The cache holds a single file, so fetching b evicts a
+	if _, err := f.Fetch(ctx, "https://example.org/b.vtt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Fetch(ctx, "https://example.org/a.vtt"); err != nil {
+		t.Fatal(err)
+	}
+	if n := client.requests["https://example.org/a.vtt"]; n != 2 {
+		t.Errorf("a.vtt fetched %d times after eviction, want 2", n)
+	}
+}
+
+func TestFetcherExpiresCues(t *testing.T) {
+	client := &fakeClient{requests: make(map[string]int)}
+	f := NewFetcher(client, 0, 10*time.Millisecond)
+	ctx := context.Background()
+
+	if _, err := f.Fetch(ctx, "https://example.org/a.vtt"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := f.Fetch(ctx, "https://example.org/a.vtt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := client.requests["https://example.org/a.vtt"]; n != 2 {
+		t.Errorf("a.vtt fetched %d times, want 2", n)
+	}
+}
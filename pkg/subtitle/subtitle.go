@@ -0,0 +1,42 @@
+// Package subtitle converts the subtitles published by the broadcasters into
+// formats understood by browsers and players. The broadcasters mostly publish
+// TTML or EBU-TT files, some already WebVTT.
+package subtitle
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+// Content types of the output formats
+const (
+	VTTContentType = "text/vtt; charset=utf-8"
+	SRTContentType = "application/x-subrip; charset=utf-8"
+)
+
+// ErrUnsupportedFormat is returned if the subtitle format isn't recognized.
+var ErrUnsupportedFormat = errors.New("unsupported subtitle format")
+
+// Cue is a single subtitle. Lines of the text are separated by newlines, any
+// styling is stripped.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Parse detects the format of the subtitle file and parses its cues.
+// Supported formats are TTML including EBU-TT and WebVTT.
+func Parse(data []byte) ([]Cue, error) {
+	trimmed := bytes.TrimLeft(data, "\xef\xbb\xbf \t\r\n")
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("WEBVTT")):
+		return parseVTT(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return parseTTML(data)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
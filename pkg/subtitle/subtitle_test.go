@@ -0,0 +1,53 @@
+package subtitle
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertTTML(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.ttml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test files")
+	}
+
+	writers := map[string]func(io.Writer, []Cue) error{
+		".vtt": WriteVTT,
+		".srt": WriteSRT,
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(file, ".ttml")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cues, err := Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for ext, write := range writers {
+				want, err := os.ReadFile(name + ext)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var buf bytes.Buffer
+				if err := write(&buf, cues); err != nil {
+					t.Fatal(err)
+				}
+				if got := buf.String(); got != string(want) {
+					t.Errorf("%s:\n%s\nwant:\n%s", ext, got, want)
+				}
+			}
+		})
+	}
+}
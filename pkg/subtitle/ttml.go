@@ -0,0 +1,296 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Time bases of TTML documents. With the media time base the times of an
+// element are relative to the begin of its parent, with smpte and clock they
+// are absolute.
+const (
+	timeBaseMedia = "media"
+	timeBaseSMPTE = "smpte"
+	timeBaseClock = "clock"
+)
+
+// xmlLineBreaks replaces the line breaks used for formatting the XML.
+var xmlLineBreaks = strings.NewReplacer("\r", " ", "\n", " ")
+
+// ttmlTiming holds the parameters needed to convert TTML time expressions,
+// see https://www.w3.org/TR/ttml1/#timing-value-timeExpression
+type ttmlTiming struct {
+	frameRate float64
+	tickRate  float64
+	timeBase  string
+}
+
+// parseTTML parses TTML and EBU-TT documents. Namespaces are ignored, since
+// broadcasters use varying prefixes. The text of each paragraph becomes a cue,
+// spans and their styling are flattened.
+//
+// The begin of body and div elements is added to the times of the paragraphs
+// they contain. EBU-TT documents often use the time code of the broadcast,
+// starting at 10:00:00 for instance. If the document gives the start of the
+// programme, it's subtracted, so the cues are relative to the movie.
+func parseTTML(data []byte) ([]Cue, error) {
+	var result []Cue
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = charset.NewReaderLabel
+
+	timing := ttmlTiming{frameRate: 30, tickRate: 1, timeBase: timeBaseMedia}
+
+	var cue *Cue
+	var text strings.Builder
+	var offsets []time.Duration
+	var programmeStart time.Duration
+	var inProgrammeStart bool
+	seenRoot := false
+
+	offset := func() time.Duration {
+		if len(offsets) == 0 {
+			return 0
+		}
+		return offsets[len(offsets)-1]
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tt":
+				seenRoot = true
+				if err := timing.parseParameters(t.Attr); err != nil {
+					return nil, err
+				}
+			case "documentStartOfProgramme":
+				inProgrammeStart = true
+				text.Reset()
+			case "body", "div":
+				start, _, err := timing.parseInterval(t.Attr)
+				if err != nil {
+					return nil, err
+				}
+				if timing.timeBase == timeBaseMedia {
+					start += offset()
+				} else {
+					start = offset()
+				}
+				offsets = append(offsets, start)
+			case "p":
+				start, end, err := timing.parseInterval(t.Attr)
+				if err != nil {
+					return nil, err
+				}
+				if timing.timeBase == timeBaseMedia {
+					start += offset()
+					end += offset()
+				}
+				cue = &Cue{Start: start - programmeStart, End: end - programmeStart}
+				if cue.Start < 0 {
+					cue.Start = 0
+				}
+				text.Reset()
+			case "br":
+				if cue != nil {
+					text.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			// Line breaks of the XML are formatting, only br elements
+			// break the lines of the cue
+			if cue != nil || inProgrammeStart {
+				text.WriteString(xmlLineBreaks.Replace(string(t)))
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "documentStartOfProgramme":
+				inProgrammeStart = false
+				programmeStart, err = timing.parseTime(text.String())
+				if err != nil {
+					return nil, err
+				}
+			case "body", "div":
+				if len(offsets) > 0 {
+					offsets = offsets[:len(offsets)-1]
+				}
+			case "p":
+				if cue == nil {
+					break
+				}
+				cue.Text = normalizeText(text.String())
+				if cue.Text != "" && cue.End > cue.Start {
+					result = append(result, *cue)
+				}
+				cue = nil
+			}
+		}
+	}
+
+	if !seenRoot {
+		return nil, ErrUnsupportedFormat
+	}
+
+	return result, nil
+}
+
+// parseParameters reads the frame and tick rate and the time base of the
+// document.
+func (t *ttmlTiming) parseParameters(attrs []xml.Attr) error {
+	multiplier := 1.0
+
+	for _, attr := range attrs {
+		var err error
+		switch attr.Name.Local {
+		case "frameRate":
+			t.frameRate, err = strconv.ParseFloat(attr.Value, 64)
+		case "tickRate":
+			t.tickRate, err = strconv.ParseFloat(attr.Value, 64)
+		case "timeBase":
+			switch attr.Value {
+			case timeBaseMedia, timeBaseSMPTE, timeBaseClock:
+				t.timeBase = attr.Value
+			default:
+				err = fmt.Errorf("unknown time base")
+			}
+		case "frameRateMultiplier":
+			var num, den float64
+			_, err = fmt.Sscanf(attr.Value, "%g %g", &num, &den)
+			if err == nil && den != 0 {
+				multiplier = num / den
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", attr.Name.Local, attr.Value)
+		}
+	}
+
+	t.frameRate *= multiplier
+	if t.frameRate <= 0 || t.tickRate <= 0 {
+		return fmt.Errorf("invalid frame or tick rate")
+	}
+
+	return nil
+}
+
+// parseInterval reads the begin and end of an element. The end may also be
+// given as duration.
+func (t *ttmlTiming) parseInterval(attrs []xml.Attr) (time.Duration, time.Duration, error) {
+	var begin, end, dur string
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "begin":
+			begin = attr.Value
+		case "end":
+			end = attr.Value
+		case "dur":
+			dur = attr.Value
+		}
+	}
+
+	start, err := t.parseTime(begin)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end == "" && dur != "" {
+		d, err := t.parseTime(dur)
+		return start, start + d, err
+	}
+
+	stop, err := t.parseTime(end)
+	return start, stop, err
+}
+
+// parseTime converts a clock time (HH:MM:SS.fff or HH:MM:SS:FF) or an offset
+// time (e.g. 12.5s, 300ms, 1200t) into a duration.
+func (t *ttmlTiming) parseTime(expr string) (time.Duration, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return 0, nil
+	}
+
+	if strings.Contains(expr, ":") {
+		var h, m int64
+		var s, frames float64
+		parts := strings.Split(expr, ":")
+		if len(parts) != 3 && len(parts) != 4 {
+			return 0, fmt.Errorf("invalid time %q", expr)
+		}
+
+		var err error
+		if h, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid time %q", expr)
+		}
+		if m, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid time %q", expr)
+		}
+		if s, err = strconv.ParseFloat(parts[2], 64); err != nil {
+			return 0, fmt.Errorf("invalid time %q", expr)
+		}
+		if len(parts) == 4 {
+			if frames, err = strconv.ParseFloat(parts[3], 64); err != nil {
+				return 0, fmt.Errorf("invalid time %q", expr)
+			}
+		}
+
+		seconds := float64(h*3600+m*60) + s + frames/t.frameRate
+		return secondsToDuration(seconds), nil
+	}
+
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"ms", 0.001},
+		{"h", 3600},
+		{"m", 60},
+		{"s", 1},
+		{"f", 1 / t.frameRate},
+		{"t", 1 / t.tickRate},
+	}
+	for _, u := range units {
+		if !strings.HasSuffix(expr, u.suffix) {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSuffix(expr, u.suffix), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", expr)
+		}
+		return secondsToDuration(v * u.factor), nil
+	}
+
+	return 0, fmt.Errorf("invalid time %q", expr)
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds*1000+0.5) * time.Millisecond
+}
+
+// normalizeText collapses the whitespace used for formatting the XML. Line
+// breaks given by br elements are kept.
+func normalizeText(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
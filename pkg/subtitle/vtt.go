@@ -0,0 +1,126 @@
+package subtitle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// vttTagPattern matches the WebVTT cue tags like <c.yellow>, <v Speaker> or
+// <00:00:01.000>.
+var vttTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// parseVTT parses a WebVTT file. Cue settings, tags and styling are dropped.
+func parseVTT(data []byte) ([]Cue, error) {
+	var result []Cue
+
+	// Blocks are separated by blank lines, the header block is skipped
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	blocks := strings.Split(string(data), "\n\n")
+
+	for _, block := range blocks[1:] {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+
+		// An optional identifier precedes the timing line
+		if len(lines) > 1 && !strings.Contains(lines[0], "-->") {
+			lines = lines[1:]
+		}
+		if len(lines) == 0 || !strings.Contains(lines[0], "-->") {
+			continue // NOTE, STYLE and REGION blocks
+		}
+
+		timing := strings.SplitN(lines[0], "-->", 2)
+		start, err := parseVTTTime(strings.TrimSpace(timing[0]))
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(timing[1])
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid cue timing %q", lines[0])
+		}
+		end, err := parseVTTTime(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		var text []string
+		for _, line := range lines[1:] {
+			line = html.UnescapeString(vttTagPattern.ReplaceAllString(line, ""))
+			if line = strings.TrimSpace(line); line != "" {
+				text = append(text, line)
+			}
+		}
+
+		if len(text) > 0 && end > start {
+			result = append(result, Cue{Start: start, End: end,
+				Text: strings.Join(text, "\n")})
+		}
+	}
+
+	return result, nil
+}
+
+// parseVTTTime parses the timestamps HH:MM:SS.mmm and MM:SS.mmm.
+func parseVTTTime(s string) (time.Duration, error) {
+	var h, m, sec, ms int
+	var err error
+
+	if strings.Count(s, ":") == 2 {
+		_, err = fmt.Sscanf(s, "%d:%d:%d.%d", &h, &m, &sec, &ms)
+	} else {
+		_, err = fmt.Sscanf(s, "%d:%d.%d", &m, &sec, &ms)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+// WriteVTT writes the cues as WebVTT file.
+func WriteVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		fmt.Fprintf(bw, "\n%s --> %s\n%s\n", formatTime(cue.Start, '.'),
+			formatTime(cue.End, '.'), escapeVTT(cue.Text))
+	}
+
+	return bw.Flush()
+}
+
+// WriteSRT writes the cues as SubRip file.
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+
+	for i, cue := range cues {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n", i+1, formatTime(cue.Start, ','),
+			formatTime(cue.End, ','), cue.Text)
+	}
+
+	return bw.Flush()
+}
+
+// formatTime formats the duration as HH:MM:SS followed by the separator and
+// the milliseconds. WebVTT uses a dot, SubRip a comma.
+func formatTime(d time.Duration, sep byte) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60,
+		ms/1000%60, sep, ms%1000)
+}
+
+// escapeVTT escapes the characters which start tags or entities in WebVTT.
+// Blank lines would end the cue, but normalized cue texts don't contain any.
+func escapeVTT(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
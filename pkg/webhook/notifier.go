@@ -0,0 +1,224 @@
+// Package webhook notifies registered receivers when a new catalog becomes
+// current. The JSON payloads are signed with HMAC-SHA256 using the secret of
+// the webhook, deliveries are retried with exponential backoff and recorded.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tschokko/mdthk-api/pkg/catalog"
+)
+
+// EventCatalogActivated is sent after a catalog became current.
+const EventCatalogActivated = "catalog.activated"
+
+// Headers of the notification requests. The signature has the form
+// "sha256=<hex>" and covers the raw request body.
+const (
+	SignatureHeader = "X-Mdthk-Signature"
+	EventHeader     = "X-Mdthk-Event"
+)
+
+// Defaults of the delivery. With the defaults the last attempt starts about
+// 30 seconds after the first one.
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = 2 * time.Second
+	DefaultTimeout     = 10 * time.Second
+)
+
+// HTTPClient is the interface of the client used to deliver notifications.
+// It's satisfied by *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Counts holds the number of entries of a catalog.
+type Counts struct {
+	Channels int `json:"channels"`
+	Topics   int `json:"topics"`
+	Movies   int `json:"movies"`
+}
+
+// Payload is the JSON body of a notification.
+type Payload struct {
+	Event       string                `json:"event"`
+	Hash        string                `json:"hash"`
+	PublishedAt time.Time             `json:"publishedAt"`
+	ImportedAt  time.Time             `json:"importedAt"`
+	Counts      Counts                `json:"counts"`
+	Changes     catalog.ChangeSummary `json:"changes"`
+	SentAt      time.Time             `json:"sentAt"`
+}
+
+// Notifier delivers notifications to all registered webhooks.
+type Notifier struct {
+	hooks    *Store
+	catalogs *catalog.Store
+	client   HTTPClient
+
+	// MaxAttempts limits the attempts per delivery, Backoff is the delay
+	// before the second attempt, which doubles with each further attempt.
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// NewNotifier creates a notifier for the webhooks of the given store. If no
+// client is given, a client with DefaultTimeout is used.
+func NewNotifier(hooks *Store, catalogs *catalog.Store, client HTTPClient) *Notifier {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &Notifier{
+		hooks:       hooks,
+		catalogs:    catalogs,
+		client:      client,
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+	}
+}
+
+// CatalogActivated notifies all webhooks about the catalog with the given
+// hash becoming current. It returns once all deliveries succeeded or gave up.
+// Failed deliveries are recorded and logged but don't cause an error.
+func (n *Notifier) CatalogActivated(ctx context.Context, hash string) error {
+	hooks, err := n.hooks.FindAll()
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	cat, err := n.catalogs.FindCatalog(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	summary, err := n.catalogs.SummarizeChanges(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(Payload{
+		Event:       EventCatalogActivated,
+		Hash:        cat.Hash,
+		PublishedAt: cat.PublishedAt,
+		ImportedAt:  cat.ImportedAt,
+		Counts: Counts{
+			Channels: cat.ChannelsCount,
+			Topics:   cat.TopicsCount,
+			Movies:   cat.MoviesCount,
+		},
+		Changes: summary,
+		SentAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, hook := range hooks {
+		wg.Add(1)
+		go func(hook Webhook) {
+			defer wg.Done()
+
+			d := n.Deliver(ctx, hook.URL, hook.secret, EventCatalogActivated, body)
+			d.WebhookID = hook.ID
+			d.CatalogHash = hash
+			if d.DeliveredAt == nil {
+				log.Printf("webhook: delivery to %s failed after %d attempts: %s",
+					hook.URL, d.Attempts, d.Error)
+			}
+			if err := n.hooks.RecordDelivery(d); err != nil {
+				log.Printf("webhook: failed to record delivery to %s: %s",
+					hook.URL, err)
+			}
+		}(hook)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// Deliver posts the body signed with the secret to the URL until it
+// succeeds, fails permanently or the attempts are exhausted. It's used for
+// the registered webhooks and by other notifications like saved searches.
+func (n *Notifier) Deliver(ctx context.Context, url, secret, event string, body []byte) Delivery {
+	d := Delivery{Event: event}
+	backoff := n.Backoff
+
+	for d.Attempts < n.MaxAttempts {
+		if d.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+				d.Error = ctx.Err().Error()
+				return d
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		d.Attempts++
+
+		status, retry, err := n.post(ctx, url, secret, event, body)
+		d.StatusCode = status
+		if err == nil {
+			now := time.Now()
+			d.DeliveredAt = &now
+			d.Error = ""
+			return d
+		}
+		d.Error = err.Error()
+		if !retry {
+			return d
+		}
+	}
+
+	return d
+}
+
+// post sends a single request. Network errors, server errors and rate limits
+// are retried, other client errors indicate a misconfigured receiver.
+func (n *Notifier) post(ctx context.Context, url, secret, event string, body []byte) (int, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url,
+		bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mdthk-webhook")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return resp.StatusCode, retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
+
+// Sign returns the signature of the body as sent in the SignatureHeader.
+// Receivers recompute it with their secret and compare both using
+// hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
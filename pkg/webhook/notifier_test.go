@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"catalog.activated"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", body); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		attempts  int
+		status    int
+		delivered bool
+	}{
+		{"success", []int{200}, 1, 200, true},
+		{"server error retried", []int{503, 500, 204}, 3, 204, true},
+		{"rate limit retried", []int{429, 200}, 2, 200, true},
+		{"client error not retried", []int{400, 200}, 1, 400, false},
+		{"attempts exhausted", []int{500, 502, 503, 200}, 3, 503, false},
+	}
+
+	body := []byte(`{"event":"catalog.activated"}`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			requests := 0
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				status := tt.statuses[requests]
+				requests++
+				mu.Unlock()
+
+				data, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Error(err)
+				}
+				if got := r.Header.Get(SignatureHeader); got != Sign("secret", data) {
+					t.Errorf("signature = %s, want %s", got, Sign("secret", data))
+				}
+				if got := r.Header.Get(EventHeader); got != EventCatalogActivated {
+					t.Errorf("event = %s, want %s", got, EventCatalogActivated)
+				}
+				rw.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			n := NewNotifier(nil, nil, srv.Client())
+			n.MaxAttempts = 3
+			n.Backoff = time.Millisecond
+
+			d := n.Deliver(context.Background(), srv.URL, "secret",
+				EventCatalogActivated, body)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if d.Attempts != tt.attempts || requests != tt.attempts {
+				t.Errorf("attempts = %d with %d requests, want %d", d.Attempts,
+					requests, tt.attempts)
+			}
+			if d.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", d.StatusCode, tt.status)
+			}
+			if (d.DeliveredAt != nil) != tt.delivered {
+				t.Errorf("delivered = %v, want %v", d.DeliveredAt != nil, tt.delivered)
+			}
+			if tt.delivered && d.Error != "" {
+				t.Errorf("error = %q, want none", d.Error)
+			}
+		})
+	}
+}
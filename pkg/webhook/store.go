@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+// secretPrefix marks webhook secrets issued by this service.
+const secretPrefix = "whsec_"
+
+// ErrWebhookNotFound is returned if a webhook doesn't exist.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// Webhook is a registered receiver of catalog notifications. The secret signs
+// the payloads, so it has to be stored in the clear. It's only returned on
+// creation.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+	secret    string
+}
+
+// Delivery records the outcome of delivering a notification to a webhook.
+type Delivery struct {
+	ID          int64      `json:"id"`
+	WebhookID   int64      `json:"webhookId"`
+	Event       string     `json:"event"`
+	CatalogHash string     `json:"catalogHash"`
+	Attempts    int        `json:"attempts"`
+	StatusCode  int        `json:"statusCode,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+}
+
+// Store manages the webhooks and their deliveries in the database.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a new webhook store on top of the given database.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// createTables creates the tables of the webhooks and the deliveries if they
+// don't exist. Deliveries are removed together with their webhook.
+func (s *Store) createTables() error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id bigserial PRIMARY KEY,
+			url varchar(2047) NOT NULL,
+			secret text NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now()
+		)`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id bigserial PRIMARY KEY,
+			webhook_id bigint NOT NULL REFERENCES webhooks ON DELETE CASCADE,
+			event varchar(64) NOT NULL,
+			catalog_hash varchar(32) NOT NULL,
+			attempts int NOT NULL,
+			status_code int,
+			error text,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			delivered_at timestamptz
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create registers a webhook for the given URL. If no secret is given, a
+// random one is generated. The secret is returned.
+func (s *Store) Create(url, secret string) (Webhook, string, error) {
+	var result Webhook
+
+	err := s.createTables()
+	if err != nil {
+		return result, "", err
+	}
+
+	if secret == "" {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			return result, "", err
+		}
+		secret = secretPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	}
+
+	err = s.db.QueryRow(
+		`INSERT INTO webhooks (url, secret) VALUES ($1, $2)
+        RETURNING id, url, created_at`, url, secret).Scan(&result.ID,
+		&result.URL, &result.CreatedAt)
+	if err != nil {
+		return result, "", err
+	}
+	result.secret = secret
+
+	return result, secret, nil
+}
+
+// FindAll returns all webhooks ordered by ID.
+func (s *Store) FindAll() ([]Webhook, error) {
+	var result []Webhook
+
+	err := s.createTables()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(
+		"SELECT id, url, secret, created_at FROM webhooks ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hook Webhook
+		if err := rows.Scan(&hook.ID, &hook.URL, &hook.secret,
+			&hook.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		result = append(result, hook)
+	}
+
+	return result, rows.Err()
+}
+
+// Delete removes the webhook with the given ID and its deliveries.
+func (s *Store) Delete(id int64) error {
+	err := s.createTables()
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec("DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// RecordDelivery stores the outcome of a delivery.
+func (s *Store) RecordDelivery(d Delivery) error {
+	err := s.createTables()
+	if err != nil {
+		return err
+	}
+
+	statusCode := sql.NullInt64{Int64: int64(d.StatusCode), Valid: d.StatusCode != 0}
+	errMsg := sql.NullString{String: d.Error, Valid: d.Error != ""}
+	_, err = s.db.Exec(
+		`INSERT INTO webhook_deliveries (webhook_id, event, catalog_hash,
+            attempts, status_code, error, delivered_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)`, d.WebhookID, d.Event,
+		d.CatalogHash, d.Attempts, statusCode, errMsg, d.DeliveredAt)
+
+	return err
+}
+
+// FindDeliveries returns the most recent deliveries, newest first. If failed
+// is set, only failed deliveries are returned.
+func (s *Store) FindDeliveries(failed bool, limit int) ([]Delivery, error) {
+	var result []Delivery
+
+	err := s.createTables()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, webhook_id, event, catalog_hash, attempts,
+            COALESCE(status_code, 0), COALESCE(error, ''), created_at,
+            delivered_at
+        FROM webhook_deliveries
+        WHERE NOT $1 OR delivered_at IS NULL
+        ORDER BY id DESC LIMIT $2`, failed, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.CatalogHash,
+			&d.Attempts, &d.StatusCode, &d.Error, &d.CreatedAt,
+			&d.DeliveredAt); err != nil {
+			return nil, err
+		}
+
+		result = append(result, d)
+	}
+
+	return result, rows.Err()
+}